@@ -5,34 +5,48 @@ import (
 	"fmt"
 	"image/color"
 	"log"
-	"os"
+	"maps"
 	"slices"
 	"strings"
 	"sync"
+	"time"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/app"
+	"fyne.io/fyne/v2/canvas"
 	"fyne.io/fyne/v2/container"
-	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/layout"
 	"fyne.io/fyne/v2/widget"
 	lua "github.com/yuin/gopher-lua"
 )
 
 type App struct {
-	db *sql.DB
+	db     *sql.DB
+	dbPath string
 
 	settingsScript string
 	l              *lua.LState
 
 	histFilePath string
-	history      []string
+	history      []HistoryEntry
 
 	mode Mode
 
 	keyBindings map[string][]string
+	keySequence *KeySequence
+	leaderKey   string
+
+	// whichKeyDelay is how long a key sequence must be pending before its
+	// possible continuations are shown. They are never shown if it is
+	// negative.
+	whichKeyDelay time.Duration
+	whichKeyTimer *time.Timer
 
 	search string
 
+	lastQueryDuration time.Duration
+	jobs              []string
+
 	// UI
 	fyneApp fyne.App
 	window  fyne.Window
@@ -40,16 +54,70 @@ type App struct {
 	content *fyne.Container
 
 	modeLabel    *ModeLabel
+	statusLine   *StatusLine
+	tabBar       *TabBar
 	commandEntry *CommandEntry
 
+	// statusLineFailed is the settings.statusline function that last failed.
+	// It is not called again, the built-in status line is shown instead until
+	// another function is set.
+	statusLineFailed *lua.LFunction
+
 	tabs            []*MultiSplit
 	currentTabIndex int
 
+	closedPanes []closedPane
+	closedTabs  []closedTab
+
+	detachedPanes      []*DetachedPane
+	activeDetachedPane *DetachedPane
+
 	toastSet *ToastSet
 
+	messageLog *MessageLog
+
 	helpDialog *HelpDialog
 
+	whichKey *WhichKey
+
 	focusedObject fyne.CanvasObject
+
+	// visualObject is the object whose rows are selected in visual mode.
+	visualObject fyne.CanvasObject
+
+	// insertObject is the object where text is typed in insert mode.
+	insertObject fyne.CanvasObject
+
+	// registers holds the text yanked to named registers.
+	registers map[string]string
+
+	// customModes holds the modes defined with mode_define, by name.
+	customModes map[string]Mode
+
+	// modeHooks holds the Lua functions of the modes defined with mode_define.
+	modeHooks map[Mode]modeHooks
+
+	// eventHandlers holds the Lua functions registered with on, by event.
+	eventHandlers map[string][]*lua.LFunction
+
+	userCommands map[string]*UserCommand
+
+	// luaDocs holds the documentation of the Lua functions and user commands
+	// by name.
+	luaDocs map[string]LuaFunctionDoc
+
+	// luaFunctionNames holds the names of the functions registered with
+	// luaRegister, to describe the key bindings using them.
+	luaFunctionNames map[*lua.LFunction]string
+
+	// helpMode is the mode whose key bindings are shown in help mode.
+	helpMode Mode
+}
+
+type modeHooks struct {
+	onEnter  *lua.LFunction
+	onLeave  *lua.LFunction
+	onSubmit *lua.LFunction
 }
 
 func NewApp(db *sql.DB, histFilePath, settingsScript string) *App {
@@ -60,11 +128,33 @@ func NewApp(db *sql.DB, histFilePath, settingsScript string) *App {
 		settingsScript: settingsScript,
 		histFilePath:   histFilePath,
 
-		mode: ModeNormal,
+		mode:     ModeNormal,
+		helpMode: ModeNormal,
+
+		keySequence: NewKeySequence(),
+		leaderKey:   DefaultLeaderKey,
+
+		whichKeyDelay: DefaultWhichKeyDelay,
+
+		registers:   map[string]string{},
+		customModes: map[string]Mode{},
+		modeHooks:   map[Mode]modeHooks{},
+
+		eventHandlers: map[string][]*lua.LFunction{},
+		userCommands:  map[string]*UserCommand{},
+		luaDocs:       map[string]LuaFunctionDoc{},
+
+		luaFunctionNames: map[*lua.LFunction]string{},
+
+		messageLog: NewMessageLog(),
 	}
 
 	modeLabel := NewModeLabel()
 
+	statusLine := NewStatusLine()
+
+	tabBar := NewTabBar()
+
 	commandEntry := NewCommandEntry()
 	commandEntry.OnCommand = func(code string) {
 		a.executeCode(code)
@@ -73,13 +163,59 @@ func NewApp(db *sql.DB, histFilePath, settingsScript string) *App {
 
 	commandEntry.OnSearch = func(search string) {
 		a.Search(search)
+		a.historyAppend(ModeSearch, search)
+		a.commandEntry.SetHistory(ModeSearch, a.historyTexts(ModeSearch))
+		a.SetMode(ModeNormal)
+	}
+
+	commandEntry.OnSQL = func(query string) {
+		if err := a.RunQuery(query); err != nil {
+			a.ToastError(fmt.Sprintf("ERROR: %v", err))
+		} else {
+			a.historyAppend(ModeSQL, query)
+			a.commandEntry.SetHistory(ModeSQL, a.historyTexts(ModeSQL))
+		}
+
 		a.SetMode(ModeNormal)
 	}
 
+	completionList := NewCompletionList()
+
+	commandEntry.Complete = a.complete
+
+	commandEntry.OnChanged = func(text string) {
+		if a.mode == ModeHelp {
+			a.helpDialog.SetFilter(text)
+		}
+	}
+	commandEntry.OnCompletionsChange = func(completions []Completion, index int) {
+		labels := make([]string, len(completions))
+		for i, c := range completions {
+			labels[i] = c.Label
+		}
+
+		completionList.SetCompletions(labels, index)
+	}
+	commandEntry.OnHistorySearchChange = func(matches []string, index int) {
+		if matches != nil && len(matches) == 0 {
+			completionList.SetLabels([]string{"No matching history entry"}, -1)
+			return
+		}
+
+		completionList.SetLabels(matches, index)
+	}
+
+	commandEntry.OnModeSubmit = func(mode Mode, text string) {
+		a.luaHookCall(a.modeHooks[mode].onSubmit, lua.LString(text))
+	}
+
 	tabs := []*MultiSplit{}
 
 	content := container.NewBorder(
-		modeLabel,
+		container.NewVBox(
+			container.NewBorder(nil, nil, modeLabel, nil, statusLine),
+			tabBar,
+		),
 		commandEntry,
 		nil,
 		nil,
@@ -88,9 +224,26 @@ func NewApp(db *sql.DB, histFilePath, settingsScript string) *App {
 
 	toastSet := NewToastSet()
 
-	helpDialog := NewHelpDialog(map[string]string{})
+	helpDialog := NewHelpDialog()
 
-	windowContent := container.NewStack(content, helpDialog, toastSet)
+	whichKey := NewWhichKey()
+	a.keySequence.OnPendingChange = a.whichKeyUpdate
+
+	// The completion list is shown over the content, right above the command
+	// entry
+	commandEntrySpacer := canvas.NewRectangle(color.Transparent)
+	commandEntrySpacer.SetMinSize(commandEntry.MinSize())
+	completionLayer := container.NewBorder(
+		nil,
+		container.NewVBox(
+			container.NewHBox(completionList, layout.NewSpacer()),
+			commandEntrySpacer,
+		),
+		nil,
+		nil,
+	)
+
+	windowContent := container.NewStack(content, completionLayer, helpDialog, whichKey, toastSet)
 
 	fyneApp := app.New()
 	window := fyneApp.NewWindow("Efin")
@@ -100,19 +253,35 @@ func NewApp(db *sql.DB, histFilePath, settingsScript string) *App {
 	a.window = window
 	a.content = content
 	a.modeLabel = modeLabel
+	a.statusLine = statusLine
+	a.tabBar = tabBar
 	a.commandEntry = commandEntry
 	a.tabs = tabs
 	a.toastSet = toastSet
 	a.helpDialog = helpDialog
+	a.whichKey = whichKey
 
 	return a
 }
 
 func (a *App) Run() {
-	a.loadHistory()
-	a.commandEntry.SetHistory(a.history)
+	dbPath, err := databasePath(a.db)
+	if err != nil {
+		a.logErrorf("could not get database path: %v", err)
+	}
+	a.dbPath = dbPath
 
 	a.initializeLuaState()
+	a.messageLogFileSet()
+
+	// The history is loaded once the settings are, for its maximum size
+	if err := a.loadHistory(); err != nil {
+		a.logErrorf("could not load history: %v", err)
+	}
+	a.commandEntry.SetHistory(ModeCommand, a.historyTexts(ModeCommand))
+	a.commandEntry.SetHistory(ModeSQL, a.historyTexts(ModeSQL))
+	a.commandEntry.SetHistory(ModeSearch, a.historyTexts(ModeSearch))
+
 	a.loadKeyBindingsDefinitions()
 
 	a.TabCreate()
@@ -121,41 +290,163 @@ func (a *App) Run() {
 
 	a.SetMode(a.mode)
 
+	a.fyneApp.Lifecycle().SetOnStarted(func() {
+		a.eventEmit(EventStartup, map[string]lua.LValue{
+			"db": lua.LString(a.dbPath),
+		})
+	})
+	a.fyneApp.Lifecycle().SetOnStopped(func() {
+		a.eventEmit(EventExit, nil)
+	})
+
+	a.window.SetMaster()
 	a.window.SetFullScreen(true)
 	a.window.Show()
 	a.window.SetFullScreen(false)
 	a.fyneApp.Run()
 	a.l.Close()
+
+	if err := a.messageLog.Close(); err != nil {
+		log.Printf("could not close message log file: %v", err)
+	}
 }
 
 func (a *App) SetMode(mode Mode) {
+	if mode == ModeVisual && a.visualObject != a.focusedObject {
+		vs, ok := a.focusedObject.(VisualSelector)
+		if !ok {
+			a.ToastMessage("Visual mode is not available for this widget")
+			return
+		}
+
+		a.visualStop()
+
+		vs.VisualStart()
+		a.visualObject = a.focusedObject
+	} else if mode != ModeVisual {
+		a.visualStop()
+	}
+
+	if mode == ModeInsert && a.insertObject != a.focusedObject {
+		ins, ok := a.focusedObject.(Inserter)
+		if !ok {
+			a.ToastMessage("Insert mode is not available for this widget")
+			return
+		}
+
+		a.insertStop()
+
+		ins.InsertStart()
+		a.insertObject = a.focusedObject
+	} else if mode != ModeInsert {
+		a.insertStop()
+	}
+
+	previous := a.mode
+	if previous != mode {
+		a.luaHookCall(a.modeHooks[previous].onLeave, lua.LString(mode.String()))
+	}
+
+	if mode == ModeHelp && previous != ModeHelp {
+		a.helpMode = previous
+		a.commandEntry.SetText("")
+		a.helpDialog.SetFilter("")
+	}
+
 	a.mode = mode
+	a.keySequence.Reset()
 
 	a.modeLabel.SetMode(mode)
 	a.commandEntry.SetMode(mode)
+	a.updateStatusLine()
 
 	a.applyKeyBindings(mode)
 
-	if mode == ModeNormal {
-		if f, ok := a.focusedObject.(fyne.Focusable); ok {
-			a.window.Canvas().Focus(f)
-		} else {
-			a.window.Canvas().Focus(nil)
+	if mode.Entry() {
+		if mode == ModeHelp {
+			a.updateHelpDialog()
+			a.helpDialog.Show()
+			a.helpDialog.Refresh()
 		}
 
+		if a.activeDetachedPane != nil {
+			a.window.RequestFocus()
+		}
+
+		a.window.Canvas().Focus(a.commandEntry)
+	} else {
+		a.focusFocusedObject()
+	}
+
+	if mode != ModeHelp {
 		a.helpDialog.Hide()
 		a.helpDialog.Refresh()
-	} else if mode == ModeHelp {
-		a.helpDialog.Show()
-		a.helpDialog.Refresh()
+	}
 
-		if f, ok := a.focusedObject.(fyne.Focusable); ok {
-			a.window.Canvas().Focus(f)
-		} else {
-			a.window.Canvas().Focus(nil)
-		}
+	if previous != mode {
+		a.luaHookCall(a.modeHooks[mode].onEnter, lua.LString(previous.String()))
+
+		a.eventEmit(EventModeChanged, map[string]lua.LValue{
+			"mode":     lua.LString(mode.String()),
+			"previous": lua.LString(previous.String()),
+		})
+	}
+}
+
+// luaHookCall calls a Lua function set as a hook, if any, and shows its
+// errors as toasts.
+func (a *App) luaHookCall(fn *lua.LFunction, args ...lua.LValue) {
+	if fn == nil {
+		return
+	}
+
+	if err := a.l.CallByParam(lua.P{
+		Fn:      fn,
+		NRet:    0,
+		Protect: true,
+	}, args...); err != nil {
+		a.ToastError(fmt.Sprintf("ERROR: %v", err))
+	}
+}
+
+func (a *App) visualStop() {
+	if vs, ok := a.visualObject.(VisualSelector); ok {
+		vs.VisualStop()
+	}
+
+	a.visualObject = nil
+}
+
+func (a *App) insertStop() {
+	if ins, ok := a.insertObject.(Inserter); ok {
+		ins.InsertStop()
+	}
+
+	a.insertObject = nil
+}
+
+// modeCheckFocus goes back to normal mode if the object with the visual
+// selection, or where text is typed in insert mode, is no longer the focused
+// one.
+func (a *App) modeCheckFocus() {
+	if (a.mode == ModeVisual && a.visualObject != a.focusedObject) ||
+		(a.mode == ModeInsert && a.insertObject != a.focusedObject) {
+		a.SetMode(ModeNormal)
+	}
+}
+
+// focusFocusedObject gives the keyboard focus to the focused object in the
+// window it is shown in.
+func (a *App) focusFocusedObject() {
+	window := a.activeWindow()
+	if a.activeDetachedPane != nil {
+		window.RequestFocus()
+	}
+
+	if f, ok := a.focusedObject.(fyne.Focusable); ok {
+		window.Canvas().Focus(f)
 	} else {
-		a.window.Canvas().Focus(a.commandEntry)
+		window.Canvas().Focus(nil)
 	}
 }
 
@@ -166,7 +457,51 @@ func (a *App) PaneCreate() {
 }
 
 func (a *App) PaneDelete() {
-	a.tabs[a.currentTabIndex].PaneDelete()
+	if dp := a.activeDetachedPane; dp != nil {
+		a.detachedPaneClose(dp)
+
+		a.closedPanes = pushBounded(a.closedPanes, closedPane{
+			tab:      dp.tab,
+			object:   dp.object,
+			position: dp.position,
+		})
+
+		return
+	}
+
+	tab := a.tabs[a.currentTabIndex]
+
+	o, position := tab.PaneRemove()
+	if o == nil {
+		return
+	}
+
+	a.closedPanes = pushBounded(a.closedPanes, closedPane{
+		tab:      tab,
+		object:   o,
+		position: position,
+	})
+}
+
+// PaneUndoClose restores the most recently deleted pane in the tab it was
+// deleted from. If that tab was deleted too, the pane is restored in the
+// current tab.
+func (a *App) PaneUndoClose() error {
+	if len(a.closedPanes) == 0 {
+		return fmt.Errorf("no closed panes")
+	}
+
+	closed := a.closedPanes[len(a.closedPanes)-1]
+	a.closedPanes = a.closedPanes[:len(a.closedPanes)-1]
+
+	if i := slices.Index(a.tabs, closed.tab); i >= 0 {
+		a.tabSwitch(i)
+		closed.tab.PaneRestore(closed.object, closed.position)
+	} else {
+		a.tabs[a.currentTabIndex].PaneCreate(closed.object)
+	}
+
+	return nil
 }
 
 func (a *App) PaneLineAdd() {
@@ -191,6 +526,38 @@ func (a *App) PaneFocusUp() {
 	a.tabs[a.currentTabIndex].PaneFocusUp()
 }
 
+func (a *App) PaneZoomToggle() {
+	a.tabs[a.currentTabIndex].PaneZoomToggle()
+}
+
+func (a *App) PaneSwap(direction Direction) {
+	a.tabs[a.currentTabIndex].PaneSwap(direction)
+}
+
+// PaneMoveToTab moves the focused pane of the current tab to the tab at index
+// i and switches to that tab.
+func (a *App) PaneMoveToTab(i int) error {
+	if i < 0 || i >= len(a.tabs) {
+		return fmt.Errorf("tab %d does not exist", i+1)
+	}
+
+	if i == a.currentTabIndex {
+		return nil
+	}
+
+	o, _ := a.tabs[a.currentTabIndex].PaneRemove()
+	if o == nil {
+		return fmt.Errorf("no pane to move")
+	}
+
+	a.tabSwitch(i)
+	a.tabs[i].PaneCreate(o)
+
+	return nil
+}
+
+// updateHelpDialog shows in the help dialog the key bindings of the mode
+// help was opened from and the ones of the focused widget.
 func (a *App) updateHelpDialog() {
 	settingsTable, ok := a.l.GetGlobal("settings").(*lua.LTable)
 	if !ok {
@@ -202,48 +569,329 @@ func (a *App) updateHelpDialog() {
 		return
 	}
 
-	kbWidget, ok := a.focusedObject.(KeyBinder)
-	if !ok {
+	sections := []HelpSection{}
+
+	if t, ok := keyBindingsTable.RawGet(lua.LString(a.helpMode.String())).(*lua.LTable); ok {
+		sections = append(sections, HelpSection{
+			Title:        fmt.Sprintf("%s mode", a.helpMode),
+			Descriptions: a.keyBindingDescriptions(t),
+		})
+	}
+
+	if kbWidget, ok := a.focusedObject.(KeyBinder); ok {
+		if kbWidgetTable, ok := keyBindingsTable.RawGet(lua.LString(kbWidget.WidgetName())).(*lua.LTable); ok {
+			descriptions := a.keyBindingDescriptions(kbWidgetTable)
+
+			t, ok := kbWidgetTable.RawGet(lua.LString(a.helpMode.String())).(*lua.LTable)
+			if ok && isKeyBindingsTable(t) {
+				maps.Copy(descriptions, a.keyBindingDescriptions(t))
+			}
+
+			sections = append(sections, HelpSection{
+				Title:        kbWidget.WidgetName(),
+				Descriptions: descriptions,
+			})
+		}
+	}
+
+	a.helpDialog.SetSections(sections)
+}
+
+// keyBindingDescriptions returns the description of every key binding of t.
+func (a *App) keyBindingDescriptions(t *lua.LTable) map[string]string {
+	descriptions := map[string]string{}
+
+	t.ForEach(func(k lua.LValue, v lua.LValue) {
+		s, ok := k.(lua.LString)
+		if !ok {
+			return
+		}
+
+		if t, ok := v.(*lua.LTable); ok && isKeyBindingsTable(t) {
+			return
+		}
+
+		descriptions[string(s)] = a.keyBindingDescription(v)
+	})
+
+	return descriptions
+}
+
+// keyBindingDescription returns the desc of a key binding defined with
+// toDescCallTable, or the description of the function it calls.
+func (a *App) keyBindingDescription(v lua.LValue) string {
+	switch v := v.(type) {
+	case *lua.LTable:
+		if desc, ok := v.RawGet(lua.LString("desc")).(lua.LString); ok {
+			return string(desc)
+		}
+
+	case *lua.LFunction:
+		if desc, ok := a.luaFunctionDescription(v); ok {
+			return desc
+		}
+	}
+
+	return "<no description>"
+}
+
+// whichKeyUpdate shows the possible continuations of the pending key
+// sequence once it has been pending for whichKeyDelay, and hides them when
+// the sequence is finished or discarded. While they are shown, the sequence
+// does not time out.
+func (a *App) whichKeyUpdate(pending string) {
+	if a.whichKeyTimer != nil {
+		a.whichKeyTimer.Stop()
+		a.whichKeyTimer = nil
+	}
+
+	if pending == "" || a.whichKeyDelay < 0 {
+		a.whichKey.Hide()
 		return
 	}
 
-	kbWidgetTable, ok := keyBindingsTable.RawGet(lua.LString(kbWidget.WidgetName())).(*lua.LTable)
-	if !ok {
+	// Once shown, the continuations follow the keys typed without delay
+	if a.whichKey.Visible() {
+		a.whichKeyShow(pending)
 		return
 	}
 
-	keyBindings := map[string]string{}
-	kbWidgetTable.ForEach(func(k lua.LValue, v lua.LValue) {
-		if s, ok := k.(lua.LString); ok {
-			description := "<no description>"
+	var timer *time.Timer
+	timer = time.AfterFunc(a.whichKeyDelay, func() {
+		fyne.Do(func() {
+			if a.whichKeyTimer != timer {
+				return
+			}
 
-			if t, ok := v.(*lua.LTable); ok {
-				if desc, ok := t.RawGet(lua.LString("desc")).(lua.LString); ok {
-					description = string(desc)
-				}
+			a.whichKeyTimer = nil
+			a.whichKeyShow(pending)
+		})
+	})
+	a.whichKeyTimer = timer
+}
+
+func (a *App) whichKeyShow(pending string) {
+	continuations := a.keyBindingContinuations(pending)
+	if len(continuations) == 0 {
+		a.whichKey.Hide()
+		return
+	}
+
+	a.whichKey.SetContinuations(pending, continuations)
+	a.whichKey.Show()
+	a.keySequence.Hold()
+}
+
+// keyBindingContinuations returns the descriptions of the key bindings of
+// the current mode and of the focused widget starting with pending, by the
+// keys remaining to type.
+func (a *App) keyBindingContinuations(pending string) map[string]string {
+	continuations := map[string]string{}
+
+	settingsTable, ok := a.l.GetGlobal("settings").(*lua.LTable)
+	if !ok {
+		return continuations
+	}
+
+	keyBindingsTable, ok := settingsTable.RawGet(lua.LString("key_bindings")).(*lua.LTable)
+	if !ok {
+		return continuations
+	}
+
+	kbs := a.keyBindings[a.mode.String()]
+	if kbWidget, ok := a.focusedObject.(KeyBinder); ok {
+		kbs = slices.Concat(
+			kbs,
+			a.keyBindings[kbWidget.WidgetName()],
+			a.keyBindings[widgetModeKeyBindings(kbWidget.WidgetName(), a.mode.String())],
+		)
+	}
+
+	tables := a.keyBindingTables(keyBindingsTable, a.mode)
+
+	for _, kb := range kbs {
+		rest, ok := strings.CutPrefix(normalizeKeySequence(kb, a.leaderKey), pending+" ")
+		if !ok {
+			continue
+		}
+
+		for _, t := range tables {
+			if v := t.RawGet(lua.LString(kb)); v != lua.LNil {
+				continuations[rest] = a.keyBindingDescription(v)
+				break
 			}
+		}
+	}
+
+	return continuations
+}
 
-			keyBindings[string(s)] = description
+// jobStart registers a running job so it is shown in the status line. The
+// returned function must be called from the UI goroutine once the job
+// finishes.
+func (a *App) jobStart(name string) func() {
+	a.jobs = append(a.jobs, name)
+	a.updateStatusLine()
+
+	return func() {
+		if i := slices.Index(a.jobs, name); i >= 0 {
+			a.jobs = slices.Delete(a.jobs, i, i+1)
+		}
+		a.updateStatusLine()
+	}
+}
+
+func (a *App) updateStatusLine() {
+	info := a.statusLineInfo()
+
+	settingsTable, ok := a.l.GetGlobal("settings").(*lua.LTable)
+	if !ok {
+		a.statusLine.SetSegments(defaultStatusLineSegments(info))
+		return
+	}
+
+	statusLineFunc, ok := settingsTable.RawGet(lua.LString("statusline")).(*lua.LFunction)
+	if !ok || statusLineFunc == a.statusLineFailed {
+		a.statusLine.SetSegments(defaultStatusLineSegments(info))
+		return
+	}
+
+	infoTable := a.l.NewTable()
+	a.l.SetField(infoTable, "mode", lua.LString(info.mode))
+	a.l.SetField(infoTable, "db", lua.LString(info.db))
+	a.l.SetField(infoTable, "widget", lua.LString(info.widget))
+	a.l.SetField(infoTable, "tab", lua.LNumber(info.tab))
+	a.l.SetField(infoTable, "tabs", lua.LNumber(info.tabs))
+	a.l.SetField(infoTable, "query_duration", lua.LNumber(info.queryDuration.Milliseconds()))
+	if info.rows >= 0 {
+		a.l.SetField(infoTable, "rows", lua.LNumber(info.rows))
+		a.l.SetField(infoTable, "row", lua.LNumber(info.row))
+	}
+	jobsTable := a.l.NewTable()
+	for _, job := range info.jobs {
+		jobsTable.Append(lua.LString(job))
+	}
+	a.l.SetField(infoTable, "jobs", jobsTable)
+
+	if err := a.l.CallByParam(lua.P{
+		Fn:      statusLineFunc,
+		NRet:    1,
+		Protect: true,
+	}, infoTable); err != nil {
+		a.logErrorf("could not evaluate statusline, using the default one: %v", err)
+		a.statusLineFailed = statusLineFunc
+		a.statusLine.SetSegments(defaultStatusLineSegments(info))
+		return
+	}
+
+	ret := a.l.Get(-1)
+	a.l.Pop(1)
+
+	segmentsTable, ok := ret.(*lua.LTable)
+	if !ok {
+		a.logErrorf("invalid statusline value, table expected, using the default one: %v", ret)
+		a.statusLineFailed = statusLineFunc
+		a.statusLine.SetSegments(defaultStatusLineSegments(info))
+		return
+	}
+
+	segments := []StatusLineSegment{}
+	segmentsTable.ForEach(func(_ lua.LValue, v lua.LValue) {
+		switch v := v.(type) {
+		case *lua.LTable:
+			segments = append(segments, StatusLineSegment{
+				Text:       lua.LVAsString(v.RawGet(lua.LString("text"))),
+				AlignRight: lua.LVAsString(v.RawGet(lua.LString("align"))) == "right",
+			})
+		default:
+			segments = append(segments, StatusLineSegment{Text: lua.LVAsString(v)})
 		}
 	})
 
-	a.helpDialog.SetDescriptions(keyBindings)
+	a.statusLine.SetSegments(segments)
+}
+
+type statusLineInfo struct {
+	mode          string
+	db            string
+	widget        string
+	tab           int
+	tabs          int
+	rows          int
+	row           int
+	queryDuration time.Duration
+	jobs          []string
+}
+
+func (a *App) statusLineInfo() statusLineInfo {
+	info := statusLineInfo{
+		mode:          a.mode.String(),
+		db:            a.dbPath,
+		tab:           a.currentTabIndex + 1,
+		tabs:          len(a.tabs),
+		rows:          -1,
+		queryDuration: a.lastQueryDuration,
+		jobs:          a.jobs,
+	}
+
+	if kb, ok := a.focusedObject.(KeyBinder); ok {
+		info.widget = kb.WidgetName()
+	}
+
+	if rc, ok := a.focusedObject.(RowCounter); ok {
+		info.rows = rc.RowCount()
+		info.row = rc.CurrentRow() + 1
+	}
+
+	return info
+}
+
+func defaultStatusLineSegments(info statusLineInfo) []StatusLineSegment {
+	segments := []StatusLineSegment{
+		{Text: info.db},
+		{Text: info.widget},
+	}
+
+	if info.rows >= 0 {
+		segments = append(segments, StatusLineSegment{Text: fmt.Sprintf("row %d/%d", info.row, info.rows)})
+	}
+
+	if info.queryDuration > 0 {
+		segments = append(segments, StatusLineSegment{Text: fmt.Sprintf("query %v", info.queryDuration.Round(time.Millisecond))})
+	}
+
+	if len(info.jobs) > 0 {
+		segments = append(segments, StatusLineSegment{
+			Text:       fmt.Sprintf("running: %s", strings.Join(info.jobs, ", ")),
+			AlignRight: true,
+		})
+	}
+
+	return segments
 }
 
 func (a *App) TabCreate() {
 	tab := NewMultiSplit()
 	tab.OnFocusMove = func(o fyne.CanvasObject) {
+		// Panes created in a tab that is not the current one, like a
+		// request loaded after switching tabs, do not take the focus
+		if a.tabs[a.currentTabIndex] != tab {
+			return
+		}
+
+		a.activeDetachedPane = nil
 		a.focusedObject = o
 
+		a.modeCheckFocus()
+
 		a.applyKeyBindingsToFocusedObject(a.mode)
 
-		if f, ok := a.focusedObject.(fyne.Focusable); ok {
-			a.window.Canvas().Focus(f)
-		} else {
-			a.window.Canvas().Focus(nil)
-		}
+		a.focusFocusedObject()
 
 		a.updateHelpDialog()
+		a.updateStatusLine()
+
+		a.focusChangedEmit()
 	}
 
 	if a.search != "" {
@@ -254,9 +902,31 @@ func (a *App) TabCreate() {
 	a.tabSwitch(len(a.tabs) - 1)
 
 	a.PaneCreate()
+
+	a.eventEmit(EventTabCreated, map[string]lua.LValue{
+		"tab": lua.LNumber(a.currentTabIndex + 1),
+	})
+}
+
+func (a *App) focusChangedEmit() {
+	widgetName := ""
+	if kb, ok := a.focusedObject.(KeyBinder); ok {
+		widgetName = kb.WidgetName()
+	}
+
+	a.eventEmit(EventFocusChanged, map[string]lua.LValue{
+		"widget":   lua.LString(widgetName),
+		"tab":      lua.LNumber(a.currentTabIndex + 1),
+		"detached": lua.LBool(a.activeDetachedPane != nil),
+	})
 }
 
 func (a *App) TabDelete() {
+	a.closedTabs = pushBounded(a.closedTabs, closedTab{
+		tab:   a.tabs[a.currentTabIndex],
+		index: a.currentTabIndex,
+	})
+
 	a.tabs = slices.Delete(a.tabs, a.currentTabIndex, a.currentTabIndex+1)
 
 	if len(a.tabs) == 0 {
@@ -267,6 +937,63 @@ func (a *App) TabDelete() {
 	a.tabSwitch(max(0, a.currentTabIndex-1))
 }
 
+// TabUndoClose restores the most recently deleted tab at its previous
+// position.
+func (a *App) TabUndoClose() error {
+	if len(a.closedTabs) == 0 {
+		return fmt.Errorf("no closed tabs")
+	}
+
+	closed := a.closedTabs[len(a.closedTabs)-1]
+	a.closedTabs = a.closedTabs[:len(a.closedTabs)-1]
+
+	i := min(closed.index, len(a.tabs))
+	a.tabs = slices.Insert(a.tabs, i, closed.tab)
+	a.tabSwitch(i)
+
+	return nil
+}
+
+// TabGoto switches to the tab at index i.
+func (a *App) TabGoto(i int) error {
+	if i < 0 || i >= len(a.tabs) {
+		return fmt.Errorf("tab %d does not exist", i+1)
+	}
+
+	a.tabSwitch(i)
+
+	return nil
+}
+
+// TabMove moves the current tab delta positions to the right, or to the left
+// if delta is negative. The tab stays within the bounds of the tab list.
+func (a *App) TabMove(delta int) {
+	target := min(max(0, a.currentTabIndex+delta), len(a.tabs)-1)
+	if target == a.currentTabIndex {
+		return
+	}
+
+	tab := a.tabs[a.currentTabIndex]
+	a.tabs = slices.Delete(a.tabs, a.currentTabIndex, a.currentTabIndex+1)
+	a.tabs = slices.Insert(a.tabs, target, tab)
+
+	a.tabSwitch(target)
+}
+
+func (a *App) TabRename(name string) {
+	a.tabs[a.currentTabIndex].Rename(name)
+	a.updateTabBar()
+}
+
+func (a *App) updateTabBar() {
+	names := make([]string, len(a.tabs))
+	for i, tab := range a.tabs {
+		names[i] = tab.Name()
+	}
+
+	a.tabBar.SetTabs(names, a.currentTabIndex)
+}
+
 func (a *App) TabNext() {
 	if a.currentTabIndex == len(a.tabs)-1 {
 		return
@@ -287,6 +1014,9 @@ func (a *App) tabSwitch(i int) {
 	a.currentTabIndex = i
 	a.content.Objects[0] = a.tabs[i]
 	a.content.Refresh()
+
+	a.updateTabBar()
+	a.tabs[i].callOnFocusMove()
 }
 
 func (a *App) MoveUp() {
@@ -297,6 +1027,8 @@ func (a *App) MoveUp() {
 	if m, ok := a.focusedObject.(Mover); ok {
 		m.MoveUp()
 	}
+
+	a.updateStatusLine()
 }
 
 func (a *App) MoveDown() {
@@ -307,6 +1039,8 @@ func (a *App) MoveDown() {
 	if m, ok := a.focusedObject.(Mover); ok {
 		m.MoveDown()
 	}
+
+	a.updateStatusLine()
 }
 
 func (a *App) MoveLeft() {
@@ -317,6 +1051,8 @@ func (a *App) MoveLeft() {
 	if m, ok := a.focusedObject.(Mover); ok {
 		m.MoveLeft()
 	}
+
+	a.updateStatusLine()
 }
 
 func (a *App) MoveRight() {
@@ -327,6 +1063,48 @@ func (a *App) MoveRight() {
 	if m, ok := a.focusedObject.(Mover); ok {
 		m.MoveRight()
 	}
+
+	a.updateStatusLine()
+}
+
+func (a *App) MovePageUp() {
+	a.move(Mover.MovePageUp)
+}
+
+func (a *App) MovePageDown() {
+	a.move(Mover.MovePageDown)
+}
+
+func (a *App) MoveHalfPageUp() {
+	a.move(Mover.MoveHalfPageUp)
+}
+
+func (a *App) MoveHalfPageDown() {
+	a.move(Mover.MoveHalfPageDown)
+}
+
+func (a *App) MoveTop() {
+	a.move(Mover.MoveTop)
+}
+
+func (a *App) MoveBottom() {
+	a.move(Mover.MoveBottom)
+}
+
+func (a *App) MoveFirstColumn() {
+	a.move(Mover.MoveFirstColumn)
+}
+
+func (a *App) MoveLastColumn() {
+	a.move(Mover.MoveLastColumn)
+}
+
+func (a *App) move(motion func(Mover)) {
+	if m, ok := a.focusedObject.(Mover); ok {
+		motion(m)
+	}
+
+	a.updateStatusLine()
 }
 
 func (a *App) Submit() {
@@ -345,6 +1123,12 @@ func (a *App) Search(search string) {
 	for _, tab := range a.tabs {
 		tab.Search(search, false)
 	}
+
+	for _, dp := range a.detachedPanes {
+		if s, ok := dp.object.(Searcher); ok {
+			s.Search(search, false)
+		}
+	}
 }
 
 func (a *App) SearchPrev() {
@@ -355,6 +1139,8 @@ func (a *App) SearchPrev() {
 	if s, ok := a.focusedObject.(Searcher); ok {
 		s.SearchPrev()
 	}
+
+	a.updateStatusLine()
 }
 
 func (a *App) SearchNext() {
@@ -365,6 +1151,8 @@ func (a *App) SearchNext() {
 	if s, ok := a.focusedObject.(Searcher); ok {
 		s.SearchNext()
 	}
+
+	a.updateStatusLine()
 }
 
 func (a *App) SearchClear() {
@@ -373,14 +1161,62 @@ func (a *App) SearchClear() {
 	for _, tab := range a.tabs {
 		tab.SearchClear()
 	}
+
+	for _, dp := range a.detachedPanes {
+		if s, ok := dp.object.(Searcher); ok {
+			s.SearchClear()
+		}
+	}
 }
 
 func (a *App) ToastMessage(message string) {
-	a.toastSet.CreateToastMessage(message)
+	a.Toast(message, ToastOptions{Level: MessageLevelInfo})
 }
 
 func (a *App) ToastError(message string) {
-	a.toastSet.CreateToastError(message)
+	a.Toast(message, ToastOptions{Level: MessageLevelError})
+}
+
+// Toast shows message as configured by options and returns the id of the
+// toast.
+func (a *App) Toast(message string, options ToastOptions) int {
+	a.messageLog.Add(options.Level, message)
+
+	return a.toastSet.CreateToast(message, options)
+}
+
+// toastOptions returns the options of a toast set in the Lua table opts, with
+// level as default level.
+func (a *App) toastOptions(opts *lua.LTable, level MessageLevel) ToastOptions {
+	options := ToastOptions{
+		Level:       level,
+		Sticky:      lua.LVAsBool(opts.RawGetString("sticky")),
+		ActionLabel: "Run",
+	}
+
+	if levelStr, ok := opts.RawGetString("level").(lua.LString); ok {
+		l, ok := MessageLevelFromString(string(levelStr))
+		if !ok {
+			a.l.ArgError(2, fmt.Sprintf("unknown level %q", levelStr))
+		}
+		options.Level = l
+	}
+
+	if duration, ok := opts.RawGetString("duration").(lua.LNumber); ok {
+		options.Duration = time.Duration(duration) * time.Millisecond
+	}
+
+	if action, ok := opts.RawGetString("action").(*lua.LFunction); ok {
+		options.Action = func() {
+			a.luaHookCall(action)
+		}
+	}
+
+	if label, ok := opts.RawGetString("action_label").(lua.LString); ok {
+		options.ActionLabel = string(label)
+	}
+
+	return options
 }
 
 func (a *App) ThemeSet(theme CustomTheme) {
@@ -388,104 +1224,439 @@ func (a *App) ThemeSet(theme CustomTheme) {
 }
 
 func (a *App) RunQuery(query string) error {
+	start := time.Now()
 	result, err := runQuery(a.db, query)
 	if err != nil {
 		return err
 	}
+	a.lastQueryDuration = time.Since(start)
 
 	resultsTable := NewTable(result)
 	resultsTable.ShowToastMessageFunc = a.ToastMessage
+	resultsTable.ShowToastErrorFunc = a.ToastError
 
 	resultsTable.OnSubmit = func(row []string) {
-		var wg sync.WaitGroup
-		var req *Request
-		var resp *Response
-		var reqErr, respErr error
+		tab := a.tabs[a.currentTabIndex]
+		jobDone := a.jobStart(fmt.Sprintf("loading request %s", row[0]))
 
-		wg.Add(1)
 		go func() {
-			defer wg.Done()
-			req, reqErr = getRequest(a.db, row[0])
-		}()
+			var wg sync.WaitGroup
+			var req *Request
+			var resp *Response
+			var reqErr, respErr error
+
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				req, reqErr = getRequest(a.db, row[0])
+			}()
+
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				resp, respErr = getResponse(a.db, row[0])
+			}()
+			wg.Wait()
+
+			fyne.Do(func() {
+				jobDone()
+
+				if reqErr != nil {
+					a.ToastError(fmt.Sprintf("ERROR: %v", reqErr))
+					return
+				}
+				if respErr != nil {
+					a.ToastError(fmt.Sprintf("ERROR: %v", respErr))
+					return
+				}
 
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			resp, respErr = getResponse(a.db, row[0])
+				reqResViewer := NewRequestResponseViewer(req, resp)
+				reqResViewer.ShowToastMessageFunc = a.ToastMessage
+				reqResViewer.ShowToastErrorFunc = a.ToastError
+
+				// The request is opened in the tab it was loaded from, even if
+				// it is no longer the current one
+				if !slices.Contains(a.tabs, tab) {
+					tab = a.tabs[a.currentTabIndex]
+				}
+				tab.PaneCreate(reqResViewer)
+
+				a.eventEmit(EventRequestOpened, map[string]lua.LValue{
+					"request": requestTable(a.l, req),
+				})
+			})
 		}()
-		wg.Wait()
+	}
 
-		if reqErr != nil {
-			a.ToastError(fmt.Sprintf("ERROR: %v", reqErr))
-			return
-		}
-		if respErr != nil {
-			a.ToastError(fmt.Sprintf("ERROR: %v", respErr))
-			return
-		}
+	// The code of a scratch buffer is not saved anywhere, so the results of
+	// the queries it runs are opened next to it instead of replacing it
+	tab := a.tabs[a.currentTabIndex]
+	if _, ok := tab.focusedPane().(*ScratchBuffer); ok {
+		tab.PaneCreate(resultsTable)
+	} else {
+		tab.SetCurrentPane(resultsTable)
+	}
+	a.tabs[a.currentTabIndex].SetDefaultName(strings.Join(strings.Fields(query), " "))
+	a.updateTabBar()
+	a.updateStatusLine()
+
+	a.eventEmit(EventQueryFinished, map[string]lua.LValue{
+		"query":    lua.LString(query),
+		"rows":     lua.LNumber(resultsTable.RowCount()),
+		"duration": lua.LNumber(a.lastQueryDuration.Milliseconds()),
+	})
 
-		reqResViewer := NewRequestResponseViewer(req, resp)
-		reqResViewer.ShowToastMessageFunc = a.ToastMessage
+	return nil
+}
 
-		a.tabs[a.currentTabIndex].PaneCreate(reqResViewer)
+// ScratchOpen opens a scratch buffer pane where code in language, "lua" or
+// "sql", is written and run.
+func (a *App) ScratchOpen(language string) error {
+	if language != ScratchLanguageLua && language != ScratchLanguageSQL {
+		return fmt.Errorf("unknown language %q", language)
 	}
 
-	a.tabs[a.currentTabIndex].SetCurrentPane(resultsTable)
+	sb := NewScratchBuffer(language)
+	sb.OnRun = a.scratchRun
+
+	a.tabs[a.currentTabIndex].PaneCreate(sb)
 
 	return nil
 }
 
+func (a *App) scratchRun(language, code string) (string, error) {
+	if language == ScratchLanguageSQL {
+		if err := a.RunQuery(code); err != nil {
+			return "", err
+		}
+
+		return fmt.Sprintf("Query finished in %s", a.lastQueryDuration), nil
+	}
+
+	output, err := a.luaRun(code)
+
+	a.loadKeyBindingsDefinitions()
+	a.updateHelpDialog()
+
+	return output, err
+}
+
+// luaRun runs code like the command line does, and returns what it prints
+// followed by the values it returns.
+func (a *App) luaRun(code string) (string, error) {
+	output := []string{}
+
+	printFunc := a.l.GetGlobal("print")
+	a.l.SetGlobal("print", a.l.NewFunction(func(ls *lua.LState) int {
+		text := luaPrintText(ls)
+
+		output = append(output, text)
+		a.messageLog.Add(MessageLevelInfo, text)
+
+		return 0
+	}))
+	defer a.l.SetGlobal("print", printFunc)
+
+	top := a.l.GetTop()
+	defer a.l.SetTop(top)
+
+	if err := a.luaExecute(code); err != nil {
+		return strings.Join(output, "\n"), err
+	}
+
+	for i := top + 1; i <= a.l.GetTop(); i++ {
+		output = append(output, a.l.ToStringMeta(a.l.Get(i)).String())
+	}
+
+	return strings.Join(output, "\n"), nil
+}
+
 func (a *App) MessageSend(message Message) {
 	if mh, ok := a.focusedObject.(MessageHandler); ok {
 		mh.MessageHandle(message)
 	}
 }
 
+// Yank copies the selected text of the focused object to register, or to the
+// clipboard if register is empty or "+". Outside of visual mode, count rows
+// from the current one are copied if count is greater than one. Visual mode
+// ends after the copy.
+func (a *App) Yank(register string, count int) {
+	ts, ok := a.focusedObject.(TextSelector)
+	if !ok {
+		return
+	}
+
+	text := ts.SelectedText()
+	if rt, ok := a.focusedObject.(RowsTexter); ok && count > 1 && a.mode != ModeVisual {
+		text = rt.RowsText(count)
+	}
+	lines := strings.Count(text, "\n") + 1
+
+	if register == "" || register == "+" {
+		if err := copyToClipboard(text); err != nil {
+			a.logErrorf("could not copy selection to clipboard: %v", err)
+			a.ToastError("Could not copy to clipboard")
+			return
+		}
+
+		a.ToastMessage(fmt.Sprintf("%d lines copied to clipboard", lines))
+	} else {
+		a.registers[register] = text
+		a.ToastMessage(fmt.Sprintf("%d lines yanked to register %s", lines, register))
+	}
+
+	if a.mode == ModeVisual {
+		a.SetMode(ModeNormal)
+	}
+}
+
+// luaOptString returns the string argument n of a Lua function, or d if there
+// is none. Numbers are ignored, as they are the count typed before a key
+// binding when the function is bound to a key.
+func luaOptString(l *lua.LState, n int, d string) string {
+	switch arg := l.Get(n).(type) {
+	case lua.LString:
+		return string(arg)
+	case lua.LNumber, *lua.LNilType:
+		return d
+	}
+
+	l.ArgError(n, "string expected")
+
+	return d
+}
+
 func (a *App) initializeLuaState() {
 	setModeFunc := a.l.NewFunction(func(ls *lua.LState) int {
 		modeStr := a.l.ToString(1)
-		mode := ModeNormal
 
-		switch strings.ToLower(modeStr) {
-		case "normal":
-			mode = ModeNormal
-		case "command":
-			mode = ModeCommand
-		case "search":
-			mode = ModeSearch
-		case "help":
-			mode = ModeHelp
+		mode, ok := a.ModeFromString(strings.ToLower(modeStr))
+		if !ok {
+			a.l.ArgError(1, fmt.Sprintf("unknown mode %q", modeStr))
+			return 0
 		}
 
 		a.SetMode(mode)
 
 		return 0
 	})
-	a.l.SetGlobal("set_mode", setModeFunc)
+	a.luaRegister(setModeFunc, "set_mode(name)",
+		"Switch to the mode called name")
+
+	modeDefineFunc := a.l.NewFunction(func(ls *lua.LState) int {
+		name := strings.ToLower(a.l.CheckString(1))
+		opts := a.l.OptTable(2, a.l.NewTable())
+
+		mode, err := a.ModeDefine(name, lua.LVAsBool(opts.RawGetString("entry")))
+		if err != nil {
+			a.l.ArgError(1, err.Error())
+			return 0
+		}
+
+		hooks := modeHooks{}
+		for field, hook := range map[string]**lua.LFunction{
+			"on_enter":  &hooks.onEnter,
+			"on_leave":  &hooks.onLeave,
+			"on_submit": &hooks.onSubmit,
+		} {
+			switch fn := opts.RawGetString(field).(type) {
+			case *lua.LFunction:
+				*hook = fn
+			case *lua.LNilType:
+			default:
+				a.l.ArgError(2, fmt.Sprintf("%s must be a function", field))
+				return 0
+			}
+		}
+		a.modeHooks[mode] = hooks
+
+		settingsTable, ok := a.l.GetGlobal("settings").(*lua.LTable)
+		if !ok {
+			return 0
+		}
+
+		keyBindingsTable, ok := settingsTable.RawGetString("key_bindings").(*lua.LTable)
+		if ok && keyBindingsTable.RawGetString(name) == lua.LNil {
+			a.l.SetField(keyBindingsTable, name, a.l.NewTable())
+		}
+
+		return 0
+	})
+	a.luaRegister(modeDefineFunc, "mode_define(name, opts)",
+		"Define a mode with its own key bindings. opts can have on_enter, on_leave and on_submit functions and entry = true to type text in the command entry")
+
+	commandDefineFunc := a.l.NewFunction(func(ls *lua.LState) int {
+		name := a.l.CheckString(1)
+		fn := a.l.CheckFunction(2)
+		opts := a.l.OptTable(3, a.l.NewTable())
+
+		nargs := "0"
+		switch v := opts.RawGetString("nargs").(type) {
+		case lua.LString:
+			nargs = string(v)
+		case lua.LNumber:
+			nargs = v.String()
+		}
+
+		uc, err := NewUserCommand(name, fn, nargs)
+		if err != nil {
+			a.l.RaiseError("could not define command: %v", err)
+			return 0
+		}
+		a.userCommands[name] = uc
+
+		a.luaDocAdd(LuaFunctionDoc{
+			Name:        name,
+			Signature:   userCommandSignature(uc),
+			Description: lua.LVAsString(opts.RawGetString("desc")),
+		})
+
+		return 0
+	})
+	a.luaRegister(commandDefineFunc, "command_define(name, fn, opts)",
+		"Define a command typed as its name followed by its arguments. opts.nargs is 0, 1, \"?\", \"*\" or \"+\", opts.desc describes it")
+
+	helpFunc := a.l.NewFunction(func(ls *lua.LState) int {
+		if err := a.Help(luaOptString(a.l, 1, "")); err != nil {
+			a.l.RaiseError("could not show help: %v", err)
+		}
+		return 0
+	})
+	a.luaRegister(helpFunc, "help([name])",
+		"Open a pane with the documentation of the function or command called name, or of all of them")
+
+	helpScrollDownFunc := a.l.NewFunction(func(ls *lua.LState) int {
+		for range a.l.OptInt(1, 1) {
+			a.helpDialog.ScrollDown()
+		}
+		return 0
+	})
+	a.luaRegister(helpScrollDownFunc, "help_scroll_down([count])",
+		"Scroll the key bindings of help mode down")
+
+	helpScrollUpFunc := a.l.NewFunction(func(ls *lua.LState) int {
+		for range a.l.OptInt(1, 1) {
+			a.helpDialog.ScrollUp()
+		}
+		return 0
+	})
+	a.luaRegister(helpScrollUpFunc, "help_scroll_up([count])",
+		"Scroll the key bindings of help mode up")
+
+	helpScrollPageDownFunc := a.l.NewFunction(func(ls *lua.LState) int {
+		for range a.l.OptInt(1, 1) {
+			a.helpDialog.ScrollPageDown()
+		}
+		return 0
+	})
+	a.luaRegister(helpScrollPageDownFunc, "help_scroll_page_down([count])",
+		"Scroll the key bindings of help mode one page down")
+
+	helpScrollPageUpFunc := a.l.NewFunction(func(ls *lua.LState) int {
+		for range a.l.OptInt(1, 1) {
+			a.helpDialog.ScrollPageUp()
+		}
+		return 0
+	})
+	a.luaRegister(helpScrollPageUpFunc, "help_scroll_page_up([count])",
+		"Scroll the key bindings of help mode one page up")
+
+	printFunc := a.l.NewFunction(func(ls *lua.LState) int {
+		text := luaPrintText(ls)
+
+		fmt.Println(text)
+		a.messageLog.Add(MessageLevelInfo, text)
+
+		return 0
+	})
+	a.luaRegister(printFunc, "print(...)",
+		"Print the values to the standard output and to the message log")
+
+	messagesFunc := a.l.NewFunction(func(ls *lua.LState) int {
+		a.Messages()
+		return 0
+	})
+	a.luaRegister(messagesFunc, "messages()",
+		"Open a pane with the toasts, errors and printed text of the session so far. The pane is not updated with the next messages")
+
+	docDefineFunc := a.l.NewFunction(func(ls *lua.LState) int {
+		a.luaDocAdd(LuaFunctionDoc{
+			Name:        a.l.CheckString(1),
+			Signature:   a.l.CheckString(2),
+			Description: a.l.OptString(3, ""),
+		})
+		return 0
+	})
+	a.luaRegister(docDefineFunc, "doc_define(name, signature, description)",
+		"Document a function so it is listed by help()")
+
+	onFunc := a.l.NewFunction(func(ls *lua.LState) int {
+		if err := a.eventOn(a.l.CheckString(1), a.l.CheckFunction(2)); err != nil {
+			a.l.ArgError(1, err.Error())
+		}
+		return 0
+	})
+	a.luaRegister(onFunc, "on(event, fn)",
+		"Call fn with the event data every time event happens: startup, exit, mode_changed, focus_changed, query_finished, request_opened or tab_created")
 
 	setModeNormalFunc := a.l.NewFunction(func(ls *lua.LState) int {
 		a.SetMode(ModeNormal)
 		return 0
 	})
-	a.l.SetGlobal("set_mode_normal", setModeNormalFunc)
+	a.luaRegister(setModeNormalFunc, "set_mode_normal()",
+		"Switch to normal mode")
+
+	setModeCommandFunc := a.l.NewFunction(func(ls *lua.LState) int {
+		a.SetMode(ModeCommand)
+		return 0
+	})
+	a.luaRegister(setModeCommandFunc, "set_mode_command()",
+		"Switch to command mode")
+
+	setModeSearchFunc := a.l.NewFunction(func(ls *lua.LState) int {
+		a.SetMode(ModeSearch)
+		return 0
+	})
+	a.luaRegister(setModeSearchFunc, "set_mode_search()",
+		"Switch to search mode")
+
+	setModeSQLFunc := a.l.NewFunction(func(ls *lua.LState) int {
+		a.SetMode(ModeSQL)
+		return 0
+	})
+	a.luaRegister(setModeSQLFunc, "set_mode_sql()",
+		"Switch to SQL mode")
+
+	setModeHelpFunc := a.l.NewFunction(func(ls *lua.LState) int {
+		a.SetMode(ModeHelp)
+		return 0
+	})
+	a.luaRegister(setModeHelpFunc, "set_mode_help()",
+		"Switch to help mode")
 
-	setModeCommandFunc := a.l.NewFunction(func(ls *lua.LState) int {
-		a.SetMode(ModeCommand)
+	setModeVisualFunc := a.l.NewFunction(func(ls *lua.LState) int {
+		a.SetMode(ModeVisual)
 		return 0
 	})
-	a.l.SetGlobal("set_mode_command", setModeCommandFunc)
+	a.luaRegister(setModeVisualFunc, "set_mode_visual()",
+		"Switch to visual mode to select rows of the focused widget")
 
-	setModeSearchFunc := a.l.NewFunction(func(ls *lua.LState) int {
-		a.SetMode(ModeSearch)
+	setModeInsertFunc := a.l.NewFunction(func(ls *lua.LState) int {
+		a.SetMode(ModeInsert)
 		return 0
 	})
-	a.l.SetGlobal("set_mode_search", setModeSearchFunc)
+	a.luaRegister(setModeInsertFunc, "set_mode_insert()",
+		"Switch to insert mode to type text in the focused scratch buffer")
 
-	setModeHelpFunc := a.l.NewFunction(func(ls *lua.LState) int {
-		a.SetMode(ModeHelp)
+	scratchOpenFunc := a.l.NewFunction(func(ls *lua.LState) int {
+		if err := a.ScratchOpen(luaOptString(a.l, 1, ScratchLanguageLua)); err != nil {
+			a.l.RaiseError("could not open scratch buffer: %v", err)
+		}
 		return 0
 	})
-	a.l.SetGlobal("set_mode_help", setModeHelpFunc)
+	a.luaRegister(scratchOpenFunc, "scratch_open([language])",
+		"Open a pane to write and run \"lua\" (the default) or \"sql\" code on several lines")
 
 	queryFunc := a.l.NewFunction(func(ls *lua.LState) int {
 		queryStr := a.l.ToString(1)
@@ -496,140 +1667,406 @@ func (a *App) initializeLuaState() {
 
 		return 0
 	})
-	a.l.SetGlobal("query", queryFunc)
+	a.luaRegister(queryFunc, "query(sql)",
+		"Run a SQL query and show its results in the current pane")
 
 	paneDeleteFunc := a.l.NewFunction(func(ls *lua.LState) int {
 		a.PaneDelete()
 		return 0
 	})
-	a.l.SetGlobal("pane_delete", paneDeleteFunc)
+	a.luaRegister(paneDeleteFunc, "pane_delete()",
+		"Close the focused pane")
 
 	paneCreateFunc := a.l.NewFunction(func(ls *lua.LState) int {
 		a.PaneCreate()
 		return 0
 	})
-	a.l.SetGlobal("pane_create", paneCreateFunc)
+	a.luaRegister(paneCreateFunc, "pane_create()",
+		"Create a pane next to the focused one")
 
 	paneLineAddFunc := a.l.NewFunction(func(ls *lua.LState) int {
 		a.PaneLineAdd()
 		return 0
 	})
-	a.l.SetGlobal("pane_line_add", paneLineAddFunc)
+	a.luaRegister(paneLineAddFunc, "pane_line_add()",
+		"Create a pane in a new line")
 
 	paneFocusUpFunc := a.l.NewFunction(func(ls *lua.LState) int {
-		a.PaneFocusUp()
+		for range a.l.OptInt(1, 1) {
+			a.PaneFocusUp()
+		}
 		return 0
 	})
-	a.l.SetGlobal("pane_focus_up", paneFocusUpFunc)
+	a.luaRegister(paneFocusUpFunc, "pane_focus_up([count])",
+		"Focus the pane above")
 
 	paneFocusDownFunc := a.l.NewFunction(func(ls *lua.LState) int {
-		a.PaneFocusDown()
+		for range a.l.OptInt(1, 1) {
+			a.PaneFocusDown()
+		}
 		return 0
 	})
-	a.l.SetGlobal("pane_focus_down", paneFocusDownFunc)
+	a.luaRegister(paneFocusDownFunc, "pane_focus_down([count])",
+		"Focus the pane below")
 
 	paneFocusLeftFunc := a.l.NewFunction(func(ls *lua.LState) int {
-		a.PaneFocusLeft()
+		for range a.l.OptInt(1, 1) {
+			a.PaneFocusLeft()
+		}
 		return 0
 	})
-	a.l.SetGlobal("pane_focus_left", paneFocusLeftFunc)
+	a.luaRegister(paneFocusLeftFunc, "pane_focus_left([count])",
+		"Focus the pane on the left")
 
 	paneFocusRightFunc := a.l.NewFunction(func(ls *lua.LState) int {
-		a.PaneFocusRight()
+		for range a.l.OptInt(1, 1) {
+			a.PaneFocusRight()
+		}
+		return 0
+	})
+	a.luaRegister(paneFocusRightFunc, "pane_focus_right([count])",
+		"Focus the pane on the right")
+
+	paneZoomFunc := a.l.NewFunction(func(ls *lua.LState) int {
+		a.PaneZoomToggle()
+		return 0
+	})
+	a.luaRegister(paneZoomFunc, "pane_zoom()",
+		"Toggle showing only the focused pane")
+
+	paneSwapFunc := a.l.NewFunction(func(ls *lua.LState) int {
+		directionStr := a.l.CheckString(1)
+
+		var direction Direction
+		switch strings.ToLower(directionStr) {
+		case "up":
+			direction = DirectionUp
+		case "down":
+			direction = DirectionDown
+		case "left":
+			direction = DirectionLeft
+		case "right":
+			direction = DirectionRight
+		default:
+			a.l.ArgError(1, fmt.Sprintf("invalid direction %q", directionStr))
+			return 0
+		}
+
+		a.PaneSwap(direction)
+		return 0
+	})
+	a.luaRegister(paneSwapFunc, "pane_swap(direction)",
+		"Swap the focused pane with its \"up\", \"down\", \"left\" or \"right\" neighbour")
+
+	paneMoveToTabFunc := a.l.NewFunction(func(ls *lua.LState) int {
+		tabNumber := a.l.CheckInt(1)
+
+		if err := a.PaneMoveToTab(tabNumber - 1); err != nil {
+			a.l.RaiseError("could not move pane: %v", err)
+		}
+
+		return 0
+	})
+	a.luaRegister(paneMoveToTabFunc, "pane_move_to_tab(n)",
+		"Move the focused pane to tab n")
+
+	paneUndoCloseFunc := a.l.NewFunction(func(ls *lua.LState) int {
+		if err := a.PaneUndoClose(); err != nil {
+			a.l.RaiseError("could not restore pane: %v", err)
+		}
+		return 0
+	})
+	a.luaRegister(paneUndoCloseFunc, "pane_undo_close()",
+		"Reopen the last closed pane")
+
+	paneDetachFunc := a.l.NewFunction(func(ls *lua.LState) int {
+		if err := a.PaneDetach(); err != nil {
+			a.l.RaiseError("could not detach pane: %v", err)
+		}
+		return 0
+	})
+	a.luaRegister(paneDetachFunc, "pane_detach()",
+		"Move the focused pane into a window of its own")
+
+	paneAttachFunc := a.l.NewFunction(func(ls *lua.LState) int {
+		if err := a.PaneAttach(); err != nil {
+			a.l.RaiseError("could not attach pane: %v", err)
+		}
 		return 0
 	})
-	a.l.SetGlobal("pane_focus_right", paneFocusRightFunc)
+	a.luaRegister(paneAttachFunc, "pane_attach()",
+		"Move a detached pane back into its tab")
 
 	commandHistoryPrevFunc := a.l.NewFunction(func(ls *lua.LState) int {
 		a.commandEntry.HistoryPrev()
 		return 0
 	})
-	a.l.SetGlobal("command_history_prev", commandHistoryPrevFunc)
+	a.luaRegister(commandHistoryPrevFunc, "command_history_prev()",
+		"Show the previous entry of the command line history")
 
 	commandHistoryNextFunc := a.l.NewFunction(func(ls *lua.LState) int {
 		a.commandEntry.HistoryNext()
 		return 0
 	})
-	a.l.SetGlobal("command_history_next", commandHistoryNextFunc)
+	a.luaRegister(commandHistoryNextFunc, "command_history_next()",
+		"Show the next entry of the command line history")
+
+	commandHistorySearchFunc := a.l.NewFunction(func(ls *lua.LState) int {
+		a.commandEntry.HistorySearch()
+		return 0
+	})
+	a.luaRegister(commandHistorySearchFunc, "command_history_search()",
+		"Search the history of the current mode as you type, or select the next older match")
 
 	tabCreateFunc := a.l.NewFunction(func(ls *lua.LState) int {
 		a.TabCreate()
 		return 0
 	})
-	a.l.SetGlobal("tab_create", tabCreateFunc)
+	a.luaRegister(tabCreateFunc, "tab_create()",
+		"Create a tab")
 
 	tabDeleteFunc := a.l.NewFunction(func(ls *lua.LState) int {
 		a.TabDelete()
 		return 0
 	})
-	a.l.SetGlobal("tab_delete", tabDeleteFunc)
+	a.luaRegister(tabDeleteFunc, "tab_delete()",
+		"Close the current tab")
 
 	tabNextFunc := a.l.NewFunction(func(ls *lua.LState) int {
-		a.TabNext()
+		for range a.l.OptInt(1, 1) {
+			a.TabNext()
+		}
 		return 0
 	})
-	a.l.SetGlobal("tab_next", tabNextFunc)
+	a.luaRegister(tabNextFunc, "tab_next([count])",
+		"Switch to the next tab")
 
 	tabPrevFunc := a.l.NewFunction(func(ls *lua.LState) int {
-		a.TabPrev()
+		for range a.l.OptInt(1, 1) {
+			a.TabPrev()
+		}
+		return 0
+	})
+	a.luaRegister(tabPrevFunc, "tab_prev([count])",
+		"Switch to the previous tab")
+
+	tabUndoCloseFunc := a.l.NewFunction(func(ls *lua.LState) int {
+		if err := a.TabUndoClose(); err != nil {
+			a.l.RaiseError("could not restore tab: %v", err)
+		}
 		return 0
 	})
-	a.l.SetGlobal("tab_prev", tabPrevFunc)
+	a.luaRegister(tabUndoCloseFunc, "tab_undo_close()",
+		"Reopen the last closed tab")
+
+	tabGotoFunc := a.l.NewFunction(func(ls *lua.LState) int {
+		tabNumber := a.l.CheckInt(1)
+
+		if err := a.TabGoto(tabNumber - 1); err != nil {
+			a.l.RaiseError("could not switch tab: %v", err)
+		}
+
+		return 0
+	})
+	a.luaRegister(tabGotoFunc, "tab_goto(n)",
+		"Switch to tab n")
+
+	tabMoveFunc := a.l.NewFunction(func(ls *lua.LState) int {
+		delta := a.l.CheckInt(1)
+		a.TabMove(delta)
+		return 0
+	})
+	a.luaRegister(tabMoveFunc, "tab_move(delta)",
+		"Move the current tab delta positions, to the left if negative")
+
+	tabRenameFunc := a.l.NewFunction(func(ls *lua.LState) int {
+		name := a.l.CheckString(1)
+		a.TabRename(name)
+		return 0
+	})
+	a.luaRegister(tabRenameFunc, "tab_rename(name)",
+		"Rename the current tab")
 
 	moveLeftFunc := a.l.NewFunction(func(ls *lua.LState) int {
-		a.MoveLeft()
+		for range a.l.OptInt(1, 1) {
+			a.MoveLeft()
+		}
 		return 0
 	})
-	a.l.SetGlobal("move_left", moveLeftFunc)
+	a.luaRegister(moveLeftFunc, "move_left([count])",
+		"Move the selection left")
 
 	moveRightFunc := a.l.NewFunction(func(ls *lua.LState) int {
-		a.MoveRight()
+		for range a.l.OptInt(1, 1) {
+			a.MoveRight()
+		}
 		return 0
 	})
-	a.l.SetGlobal("move_right", moveRightFunc)
+	a.luaRegister(moveRightFunc, "move_right([count])",
+		"Move the selection right")
 
 	moveUpFunc := a.l.NewFunction(func(ls *lua.LState) int {
-		a.MoveUp()
+		for range a.l.OptInt(1, 1) {
+			a.MoveUp()
+		}
 		return 0
 	})
-	a.l.SetGlobal("move_up", moveUpFunc)
+	a.luaRegister(moveUpFunc, "move_up([count])",
+		"Move the selection up")
 
 	moveDownFunc := a.l.NewFunction(func(ls *lua.LState) int {
-		a.MoveDown()
+		for range a.l.OptInt(1, 1) {
+			a.MoveDown()
+		}
 		return 0
 	})
-	a.l.SetGlobal("move_down", moveDownFunc)
+	a.luaRegister(moveDownFunc, "move_down([count])",
+		"Move the selection down")
+
+	movePageUpFunc := a.l.NewFunction(func(ls *lua.LState) int {
+		for range a.l.OptInt(1, 1) {
+			a.MovePageUp()
+		}
+		return 0
+	})
+	a.luaRegister(movePageUpFunc, "move_page_up([count])",
+		"Move the selection one page up")
+
+	movePageDownFunc := a.l.NewFunction(func(ls *lua.LState) int {
+		for range a.l.OptInt(1, 1) {
+			a.MovePageDown()
+		}
+		return 0
+	})
+	a.luaRegister(movePageDownFunc, "move_page_down([count])",
+		"Move the selection one page down")
+
+	moveHalfPageUpFunc := a.l.NewFunction(func(ls *lua.LState) int {
+		for range a.l.OptInt(1, 1) {
+			a.MoveHalfPageUp()
+		}
+		return 0
+	})
+	a.luaRegister(moveHalfPageUpFunc, "move_half_page_up([count])",
+		"Move the selection half a page up")
+
+	moveHalfPageDownFunc := a.l.NewFunction(func(ls *lua.LState) int {
+		for range a.l.OptInt(1, 1) {
+			a.MoveHalfPageDown()
+		}
+		return 0
+	})
+	a.luaRegister(moveHalfPageDownFunc, "move_half_page_down([count])",
+		"Move the selection half a page down")
+
+	moveTopFunc := a.l.NewFunction(func(ls *lua.LState) int {
+		a.MoveTop()
+		return 0
+	})
+	a.luaRegister(moveTopFunc, "move_top()",
+		"Move the selection to the first row")
+
+	moveBottomFunc := a.l.NewFunction(func(ls *lua.LState) int {
+		a.MoveBottom()
+		return 0
+	})
+	a.luaRegister(moveBottomFunc, "move_bottom()",
+		"Move the selection to the last row")
+
+	moveFirstColumnFunc := a.l.NewFunction(func(ls *lua.LState) int {
+		a.MoveFirstColumn()
+		return 0
+	})
+	a.luaRegister(moveFirstColumnFunc, "move_first_column()",
+		"Move the selection to the first column")
+
+	moveLastColumnFunc := a.l.NewFunction(func(ls *lua.LState) int {
+		a.MoveLastColumn()
+		return 0
+	})
+	a.luaRegister(moveLastColumnFunc, "move_last_column()",
+		"Move the selection to the last column")
 
 	submitFunc := a.l.NewFunction(func(ls *lua.LState) int {
 		a.Submit()
 		return 0
 	})
-	a.l.SetGlobal("submit", submitFunc)
+	a.luaRegister(submitFunc, "submit()",
+		"Open the selected row of the focused widget")
 
 	searchResultPrevFunc := a.l.NewFunction(func(ls *lua.LState) int {
-		a.SearchPrev()
+		for range a.l.OptInt(1, 1) {
+			a.SearchPrev()
+		}
 		return 0
 	})
-	a.l.SetGlobal("search_result_prev", searchResultPrevFunc)
+	a.luaRegister(searchResultPrevFunc, "search_result_prev([count])",
+		"Go to the previous search result")
 
 	searchResultNextFunc := a.l.NewFunction(func(ls *lua.LState) int {
-		a.SearchNext()
+		for range a.l.OptInt(1, 1) {
+			a.SearchNext()
+		}
 		return 0
 	})
-	a.l.SetGlobal("search_result_next", searchResultNextFunc)
+	a.luaRegister(searchResultNextFunc, "search_result_next([count])",
+		"Go to the next search result")
 
 	searchClearFunc := a.l.NewFunction(func(ls *lua.LState) int {
 		a.SearchClear()
 		return 0
 	})
-	a.l.SetGlobal("search_clear", searchClearFunc)
+	a.luaRegister(searchClearFunc, "search_clear()",
+		"Clear the search results")
 
 	messageSendFunc := a.l.NewFunction(func(ls *lua.LState) int {
 		message := a.l.ToString(1)
 		a.MessageSend(message)
 		return 0
 	})
-	a.l.SetGlobal("message_send", messageSendFunc)
+	a.luaRegister(messageSendFunc, "message_send(message)",
+		"Send a message to the focused widget")
+
+	yankFunc := a.l.NewFunction(func(ls *lua.LState) int {
+		register := ""
+		count := 1
+
+		switch arg := a.l.Get(1).(type) {
+		case lua.LString:
+			register = string(arg)
+		case lua.LNumber:
+			count = int(arg)
+		case *lua.LNilType:
+		default:
+			a.l.ArgError(1, "register name or count expected")
+		}
+
+		a.Yank(register, count)
+		return 0
+	})
+	a.luaRegister(yankFunc, "yank([register])",
+		"Copy the selection, or the current row, to register or to the clipboard. "+
+			"A count instead of register, as in 3y, copies that many rows from the current one")
+
+	registerGetFunc := a.l.NewFunction(func(ls *lua.LState) int {
+		text, ok := a.registers[a.l.CheckString(1)]
+		if !ok {
+			a.l.Push(lua.LNil)
+			return 1
+		}
+
+		a.l.Push(lua.LString(text))
+		return 1
+	})
+	a.luaRegister(registerGetFunc, "register_get(name)",
+		"Return the text of a register, or nil if it is empty")
+
+	registerSetFunc := a.l.NewFunction(func(ls *lua.LState) int {
+		a.registers[a.l.CheckString(1)] = a.l.CheckString(2)
+		return 0
+	})
+	a.luaRegister(registerSetFunc, "register_set(name, text)",
+		"Set the text of a register")
 
 	messageSend := func(message string) *lua.LFunction {
 		return a.l.NewFunction(func(ls *lua.LState) int {
@@ -640,21 +2077,66 @@ func (a *App) initializeLuaState() {
 
 	toastFunc := a.l.NewFunction(func(ls *lua.LState) int {
 		message := a.l.ToString(1)
+		options := a.toastOptions(a.l.OptTable(2, a.l.NewTable()), MessageLevelInfo)
 
-		a.ToastMessage(message)
+		a.l.Push(lua.LNumber(a.Toast(message, options)))
 
-		return 0
+		return 1
 	})
-	a.l.SetGlobal("toast", toastFunc)
+	a.luaRegister(toastFunc, "toast(message, [opts])",
+		"Show a message and return its id. opts.level is \"info\", \"success\", \"warning\" or \"error\", "+
+			"opts.duration is in milliseconds, opts.sticky keeps it until it is dismissed, "+
+			"opts.action is a function run by a button labeled opts.action_label")
 
 	toastErrFunc := a.l.NewFunction(func(ls *lua.LState) int {
 		message := a.l.ToString(1)
+		options := a.toastOptions(a.l.OptTable(2, a.l.NewTable()), MessageLevelError)
+
+		a.l.Push(lua.LNumber(a.Toast(message, options)))
+
+		return 1
+	})
+	a.luaRegister(toastErrFunc, "toast_err(message, [opts])",
+		"Show an error message and return its id, opts are the ones of toast")
+
+	toastConfigFunc := a.l.NewFunction(func(ls *lua.LState) int {
+		config := a.l.CheckTable(1)
 
-		a.ToastError(message)
+		if duration, ok := config.RawGetString("duration").(lua.LNumber); ok {
+			a.toastSet.Duration = time.Duration(duration) * time.Millisecond
+		}
+
+		if maxVisible, ok := config.RawGetString("max_visible").(lua.LNumber); ok {
+			a.toastSet.MaxVisible = int(maxVisible)
+		}
+
+		return 0
+	})
+	a.luaRegister(toastConfigFunc, "toast_config(config)",
+		"Set the duration in milliseconds of the toasts with config.duration, "+
+			"and the number of toasts shown at most with config.max_visible")
+
+	toastDismissFunc := a.l.NewFunction(func(ls *lua.LState) int {
+		if id := a.l.OptInt(1, 0); id != 0 {
+			a.toastSet.Dismiss(id)
+		} else {
+			a.toastSet.DismissAll()
+		}
+
+		return 0
+	})
+	a.luaRegister(toastDismissFunc, "toast_dismiss([id])",
+		"Dismiss the toast with id, or all the toasts")
+
+	toastActionFunc := a.l.NewFunction(func(ls *lua.LState) int {
+		if !a.toastSet.RunAction(a.l.OptInt(1, 0)) {
+			a.l.RaiseError("no toast with an action found")
+		}
 
 		return 0
 	})
-	a.l.SetGlobal("toast_err", toastErrFunc)
+	a.luaRegister(toastActionFunc, "toast_action([id])",
+		"Run the action of the toast with id, or of the newest toast with an action, and dismiss it")
 
 	themeSetFunc := a.l.NewFunction(func(ls *lua.LState) int {
 		themeTable := a.l.ToTable(1)
@@ -715,36 +2197,59 @@ func (a *App) initializeLuaState() {
 
 		return 0
 	})
-	a.l.SetGlobal("theme_set", themeSetFunc)
+	a.luaRegister(themeSetFunc, "theme_set(theme)",
+		"Set the colors of the theme table, like themes.default")
 
 	settingsTable := a.l.NewTable()
 	keyBindingsTable := a.l.NewTable()
 	normalModeTable := a.l.NewTable()
 	commandModeTable := a.l.NewTable()
 	searchModeTable := a.l.NewTable()
+	sqlModeTable := a.l.NewTable()
 	helpModeTable := a.l.NewTable()
+	visualModeTable := a.l.NewTable()
+	insertModeTable := a.l.NewTable()
 
 	a.l.SetField(settingsTable, "key_bindings", keyBindingsTable)
 	a.l.SetField(keyBindingsTable, "normal", normalModeTable)
 	a.l.SetField(keyBindingsTable, "command", commandModeTable)
 	a.l.SetField(keyBindingsTable, "search", searchModeTable)
+	a.l.SetField(keyBindingsTable, "sql", sqlModeTable)
 	a.l.SetField(keyBindingsTable, "help", helpModeTable)
+	a.l.SetField(keyBindingsTable, "visual", visualModeTable)
+	a.l.SetField(keyBindingsTable, "insert", insertModeTable)
 
-	a.l.SetField(normalModeTable, "ctrl i", setModeCommandFunc)
-	a.l.SetField(normalModeTable, "ctrl /", setModeSearchFunc)
-	a.l.SetField(normalModeTable, "ctrl q", setModeHelpFunc)
+	a.l.SetField(normalModeTable, ":", setModeCommandFunc)
+	a.l.SetField(normalModeTable, "/", setModeSearchFunc)
+	a.l.SetField(normalModeTable, "?", setModeHelpFunc)
 	a.l.SetField(normalModeTable, "escape", searchClearFunc)
 
 	a.l.SetField(commandModeTable, "escape", setModeNormalFunc)
 	a.l.SetField(commandModeTable, "ctrl j", commandHistoryNextFunc)
 	a.l.SetField(commandModeTable, "ctrl k", commandHistoryPrevFunc)
+	a.l.SetField(commandModeTable, "ctrl r", commandHistorySearchFunc)
 
 	a.l.SetField(searchModeTable, "escape", setModeNormalFunc)
+	a.l.SetField(searchModeTable, "ctrl j", commandHistoryNextFunc)
+	a.l.SetField(searchModeTable, "ctrl k", commandHistoryPrevFunc)
+	a.l.SetField(searchModeTable, "ctrl r", commandHistorySearchFunc)
+
+	a.l.SetField(normalModeTable, "q", setModeSQLFunc)
+	a.l.SetField(sqlModeTable, "escape", setModeNormalFunc)
+	a.l.SetField(sqlModeTable, "ctrl j", commandHistoryNextFunc)
+	a.l.SetField(sqlModeTable, "ctrl k", commandHistoryPrevFunc)
+	a.l.SetField(sqlModeTable, "ctrl r", commandHistorySearchFunc)
 
 	a.l.SetField(helpModeTable, "escape", setModeNormalFunc)
-	a.l.SetField(helpModeTable, "ctrl i", setModeCommandFunc)
-	a.l.SetField(helpModeTable, "ctrl /", setModeSearchFunc)
-	a.l.SetField(helpModeTable, "ctrl q", setModeHelpFunc)
+
+	a.l.SetField(normalModeTable, "v", setModeVisualFunc)
+	a.l.SetField(visualModeTable, "escape", setModeNormalFunc)
+	a.l.SetField(visualModeTable, "v", setModeNormalFunc)
+
+	a.l.SetField(insertModeTable, "escape", setModeNormalFunc)
+
+	a.l.SetField(normalModeTable, "y", yankFunc)
+	a.l.SetField(visualModeTable, "y", yankFunc)
 
 	a.l.SetField(normalModeTable, "ctrl d", paneDeleteFunc)
 	a.l.SetField(normalModeTable, "ctrl n", paneCreateFunc)
@@ -760,6 +2265,14 @@ func (a *App) initializeLuaState() {
 	a.l.SetField(helpModeTable, "ctrl k", paneFocusUpFunc)
 	a.l.SetField(helpModeTable, "ctrl j", paneFocusDownFunc)
 
+	a.l.SetField(normalModeTable, "ctrl u", paneUndoCloseFunc)
+	a.l.SetField(helpModeTable, "ctrl u", paneUndoCloseFunc)
+	a.l.SetField(normalModeTable, "ctrl shift u", tabUndoCloseFunc)
+	a.l.SetField(helpModeTable, "ctrl shift u", tabUndoCloseFunc)
+
+	a.l.SetField(normalModeTable, "ctrl m", paneZoomFunc)
+	a.l.SetField(helpModeTable, "ctrl m", paneZoomFunc)
+
 	a.l.SetField(normalModeTable, "ctrl shift n", tabCreateFunc)
 	a.l.SetField(normalModeTable, "ctrl shift d", tabDeleteFunc)
 	a.l.SetField(normalModeTable, "ctrl shift h", tabPrevFunc)
@@ -769,37 +2282,87 @@ func (a *App) initializeLuaState() {
 	a.l.SetField(helpModeTable, "ctrl shift h", tabPrevFunc)
 	a.l.SetField(helpModeTable, "ctrl shift l", tabNextFunc)
 
+	for i := 1; i <= 9; i++ {
+		tabGotoNFunc := a.toDescCallTable(fmt.Sprintf("Go to tab %d", i), a.l.NewFunction(func(ls *lua.LState) int {
+			a.TabGoto(i - 1)
+			return 0
+		}))
+		a.l.SetField(normalModeTable, fmt.Sprintf("alt %d", i), tabGotoNFunc)
+		a.l.SetField(helpModeTable, fmt.Sprintf("alt %d", i), tabGotoNFunc)
+	}
+
 	a.l.SetField(normalModeTable, "n", searchResultNextFunc)
 	a.l.SetField(normalModeTable, "p", searchResultPrevFunc)
-	a.l.SetField(helpModeTable, "n", searchResultNextFunc)
-	a.l.SetField(helpModeTable, "p", searchResultPrevFunc)
+	a.l.SetField(normalModeTable, "N", searchResultPrevFunc)
+	a.l.SetField(helpModeTable, "down", helpScrollDownFunc)
+	a.l.SetField(helpModeTable, "up", helpScrollUpFunc)
+	a.l.SetField(helpModeTable, "next", helpScrollPageDownFunc)
+	a.l.SetField(helpModeTable, "prior", helpScrollPageUpFunc)
 
 	a.l.SetField(normalModeTable, "h", moveLeftFunc)
 	a.l.SetField(normalModeTable, "l", moveRightFunc)
 	a.l.SetField(normalModeTable, "k", moveUpFunc)
 	a.l.SetField(normalModeTable, "j", moveDownFunc)
-	a.l.SetField(helpModeTable, "h", moveLeftFunc)
-	a.l.SetField(helpModeTable, "l", moveRightFunc)
-	a.l.SetField(helpModeTable, "k", moveUpFunc)
-	a.l.SetField(helpModeTable, "j", moveDownFunc)
+	a.l.SetField(visualModeTable, "h", moveLeftFunc)
+	a.l.SetField(visualModeTable, "l", moveRightFunc)
+	a.l.SetField(visualModeTable, "k", moveUpFunc)
+	a.l.SetField(visualModeTable, "j", moveDownFunc)
+
+	a.l.SetField(normalModeTable, "ctrl f", movePageDownFunc)
+	a.l.SetField(normalModeTable, "ctrl b", movePageUpFunc)
+	a.l.SetField(normalModeTable, "next", movePageDownFunc)
+	a.l.SetField(normalModeTable, "prior", movePageUpFunc)
+	a.l.SetField(normalModeTable, "g g", moveTopFunc)
+	a.l.SetField(normalModeTable, "G", moveBottomFunc)
+	a.l.SetField(normalModeTable, "0", moveFirstColumnFunc)
+	a.l.SetField(normalModeTable, "$", moveLastColumnFunc)
+	a.l.SetField(normalModeTable, "home", moveTopFunc)
+	a.l.SetField(normalModeTable, "end", moveBottomFunc)
+	a.l.SetField(visualModeTable, "ctrl f", movePageDownFunc)
+	a.l.SetField(visualModeTable, "ctrl b", movePageUpFunc)
+	a.l.SetField(visualModeTable, "next", movePageDownFunc)
+	a.l.SetField(visualModeTable, "prior", movePageUpFunc)
+	a.l.SetField(visualModeTable, "g g", moveTopFunc)
+	a.l.SetField(visualModeTable, "G", moveBottomFunc)
+	a.l.SetField(visualModeTable, "home", moveTopFunc)
+	a.l.SetField(visualModeTable, "end", moveBottomFunc)
 
 	a.l.SetField(normalModeTable, "enter", submitFunc)
 	a.l.SetField(normalModeTable, "return", submitFunc)
-	a.l.SetField(helpModeTable, "enter", submitFunc)
-	a.l.SetField(helpModeTable, "return", submitFunc)
+	a.l.SetField(visualModeTable, "enter", submitFunc)
+	a.l.SetField(visualModeTable, "return", submitFunc)
 
 	tableTable := a.l.NewTable()
 	a.l.SetField(keyBindingsTable, "table", tableTable)
-	a.l.SetField(tableTable, "c", toDescCallTable(a.l, "Copy row to clipboard", messageSend(TableMessageCopyRow)))
+	for _, mode := range []Mode{ModeNormal, ModeVisual} {
+		tableModeTable := a.l.NewTable()
+		a.l.SetField(tableTable, mode.String(), tableModeTable)
+		a.l.SetField(tableModeTable, "c", a.toDescCallTable("Copy row to clipboard", messageSend(TableMessageCopyRow)))
+	}
 
 	requestResponseViewerTable := a.l.NewTable()
 	a.l.SetField(keyBindingsTable, "request_response_viewer", requestResponseViewerTable)
-	a.l.SetField(requestResponseViewerTable, "c",
-		toDescCallTable(a.l, "Copy request to clipboard", messageSend(RequestResponseViewerMessageCopyRequest)))
-	a.l.SetField(requestResponseViewerTable, "s",
-		toDescCallTable(a.l, "Copy request script to clipboard", messageSend(RequestResponseViewerMessageCopyRequestScript)))
-	a.l.SetField(requestResponseViewerTable, "r",
-		toDescCallTable(a.l, "Copy request response to clipboad", messageSend(RequestResponseViewerMessageCopyResponse)))
+	requestResponseViewerNormalTable := a.l.NewTable()
+	a.l.SetField(requestResponseViewerTable, ModeNormal.String(), requestResponseViewerNormalTable)
+	a.l.SetField(requestResponseViewerNormalTable, "c",
+		a.toDescCallTable("Copy request to clipboard", messageSend(RequestResponseViewerMessageCopyRequest)))
+	a.l.SetField(requestResponseViewerNormalTable, "s",
+		a.toDescCallTable("Copy request script to clipboard", messageSend(RequestResponseViewerMessageCopyRequestScript)))
+	a.l.SetField(requestResponseViewerNormalTable, "r",
+		a.toDescCallTable("Copy request response to clipboad", messageSend(RequestResponseViewerMessageCopyResponse)))
+
+	scratchBufferTable := a.l.NewTable()
+	a.l.SetField(keyBindingsTable, "scratch_buffer", scratchBufferTable)
+	scratchBufferNormalTable := a.l.NewTable()
+	a.l.SetField(scratchBufferTable, ModeNormal.String(), scratchBufferNormalTable)
+	a.l.SetField(scratchBufferNormalTable, "i", setModeInsertFunc)
+	a.l.SetField(scratchBufferNormalTable, "r",
+		a.toDescCallTable("Run the whole scratch buffer", messageSend(ScratchBufferMessageRunBuffer)))
+	scratchBufferInsertTable := a.l.NewTable()
+	a.l.SetField(scratchBufferTable, ModeInsert.String(), scratchBufferInsertTable)
+	runParagraph := a.toDescCallTable("Run the paragraph under the cursor", messageSend(ScratchBufferMessageRunParagraph))
+	a.l.SetField(scratchBufferInsertTable, "ctrl return", runParagraph)
+	a.l.SetField(scratchBufferInsertTable, "ctrl enter", runParagraph)
 
 	a.l.SetGlobal("settings", settingsTable)
 
@@ -809,74 +2372,54 @@ func (a *App) initializeLuaState() {
 }
 
 func (a *App) executeCode(code string) {
-	var f *lua.LFunction
-	f, err := a.l.LoadString("return " + code)
-	if err != nil {
-		f, err = a.l.LoadString(code)
-		if err != nil {
-			a.ToastError(fmt.Sprintf("ERROR: %v", err))
-			return
-		}
-	}
+	top := a.l.GetTop()
+	err := a.luaExecute(code)
+	a.l.SetTop(top)
 
-	if err := a.l.CallByParam(lua.P{
-		Fn:      f,
-		NRet:    0,
-		Protect: true,
-	}); err != nil {
+	if err != nil {
 		a.ToastError(fmt.Sprintf("ERROR: %v", err))
-	}
-
-	if err == nil {
-		a.historyAppend(code)
-		a.commandEntry.SetHistory(a.history)
+	} else {
+		a.historyAppend(ModeCommand, code)
+		a.commandEntry.SetHistory(ModeCommand, a.historyTexts(ModeCommand))
 	}
 
 	a.loadKeyBindingsDefinitions()
 	a.updateHelpDialog()
 }
 
-func (a *App) historyAppend(cmd string) error {
-	f, err := os.OpenFile(a.histFilePath, os.O_APPEND|os.O_WRONLY|os.O_CREATE, 0600)
-	if err != nil {
-		return err
-	}
-	defer f.Close()
-
-	if _, err := f.Write([]byte(cmd + "\n")); err != nil {
-		return err
-	}
-
-	a.history = append(a.history, cmd)
-
-	return nil
-}
-
-func (a *App) loadHistory() error {
-	fbytes, err := os.ReadFile(a.histFilePath)
-	if err != nil && !os.IsNotExist(err) {
-		return err
+// luaExecute runs code typed as in command mode: a user command followed by
+// its arguments, or else a Lua expression or chunk. The values returned by
+// the code are left on the stack.
+func (a *App) luaExecute(code string) error {
+	if uc, ok := a.userCommandFind(code); ok {
+		return a.userCommandExecute(uc, code)
 	}
 
-	a.history = []string{}
-	content := string(fbytes)
-	for line := range strings.Lines(content) {
-		a.history = append(a.history, strings.TrimRight(line, "\n"))
+	f, err := a.l.LoadString("return " + code)
+	if err != nil {
+		f, err = a.l.LoadString(code)
+		if err != nil {
+			return err
+		}
 	}
 
-	return nil
+	return a.l.CallByParam(lua.P{
+		Fn:      f,
+		NRet:    lua.MultRet,
+		Protect: true,
+	})
 }
 
 func (a *App) loadKeyBindingsDefinitions() {
 	settingsTable, ok := a.l.GetGlobal("settings").(*lua.LTable)
 	if !ok {
-		log.Printf("invalid settings table found")
+		a.logErrorf("invalid settings table found")
 		return
 	}
 
 	keyBindingsTable, ok := settingsTable.RawGet(lua.LString("key_bindings")).(*lua.LTable)
 	if !ok {
-		log.Printf("invalid key_bindings table found")
+		a.logErrorf("invalid key_bindings table found")
 		return
 	}
 
@@ -891,7 +2434,7 @@ func (a *App) loadKeyBindingsDefinitions() {
 		modeLS, ok := k.(lua.LString)
 		if !ok {
 			err = fmt.Errorf("invalid mode found in key_bindings table: %+v", k)
-			log.Printf(err.Error())
+			a.logErrorf("%v", err)
 			return
 		}
 		mode := modeLS.String()
@@ -899,7 +2442,7 @@ func (a *App) loadKeyBindingsDefinitions() {
 		modeKeyBindingsTable, ok := v.(*lua.LTable)
 		if !ok {
 			err = fmt.Errorf("invalid value found in key_bindings table for %s mode", mode)
-			log.Printf(err.Error())
+			a.logErrorf("%v", err)
 			return
 		}
 
@@ -912,7 +2455,18 @@ func (a *App) loadKeyBindingsDefinitions() {
 			kbStr, ok := kbK.(lua.LString)
 			if !ok {
 				err = fmt.Errorf("invalid key binding found in %s mode key_bindings", mode)
-				log.Printf(err.Error())
+				a.logErrorf("%v", err)
+				return
+			}
+
+			// Widget key bindings for a single mode, as in key_bindings.table.normal
+			if t, ok := kbV.(*lua.LTable); ok && isKeyBindingsTable(t) {
+				widgetMode := widgetModeKeyBindings(mode, kbStr.String())
+				t.ForEach(func(k lua.LValue, _ lua.LValue) {
+					if s, ok := k.(lua.LString); ok {
+						keyBindings[widgetMode] = append(keyBindings[widgetMode], s.String())
+					}
+				})
 				return
 			}
 
@@ -921,158 +2475,192 @@ func (a *App) loadKeyBindingsDefinitions() {
 	})
 
 	if err != nil {
-		log.Printf("could not load keybindings: %v", err)
+		a.logErrorf("could not load keybindings: %v", err)
 		return
 	}
 
 	a.keyBindings = keyBindings
+
+	a.leaderKey = DefaultLeaderKey
+	if leader, ok := settingsTable.RawGet(lua.LString("leader")).(lua.LString); ok {
+		a.leaderKey = string(leader)
+	}
+
+	a.keySequence.Timeout = DefaultKeySequenceTimeout
+	if timeout, ok := settingsTable.RawGet(lua.LString("key_sequence_timeout")).(lua.LNumber); ok {
+		a.keySequence.Timeout = time.Duration(timeout) * time.Millisecond
+	}
+
+	a.whichKeyDelay = DefaultWhichKeyDelay
+	if delay, ok := settingsTable.RawGet(lua.LString("which_key_delay")).(lua.LNumber); ok {
+		a.whichKeyDelay = time.Duration(delay) * time.Millisecond
+	}
 }
 
-func (a *App) executeKeyBinding(kb string) {
+// executeKeyBinding runs the function bound to kb in the current mode. If a
+// count was typed before the key binding, it is passed as argument.
+func (a *App) executeKeyBinding(kb string, count int) {
 	settingsTable, ok := a.l.GetGlobal("settings").(*lua.LTable)
 	if !ok {
-		log.Printf("invalid settings table found")
+		a.logErrorf("invalid settings table found")
 		return
 	}
 
 	keyBindingsTable, ok := settingsTable.RawGet(lua.LString("key_bindings")).(*lua.LTable)
 	if !ok {
-		log.Printf("invalid key_bindings table found")
-		return
-	}
-
-	kbModeTable, ok := keyBindingsTable.RawGet(lua.LString(a.mode.String())).(*lua.LTable)
-	if !ok {
-		log.Printf("key binding table not available for mode %s", a.mode)
+		a.logErrorf("invalid key_bindings table found")
 		return
 	}
 
-	kbCall := kbModeTable.RawGet(lua.LString(kb))
 	var kbFunc *lua.LFunction
-	switch kbCall.Type() {
-	case lua.LTFunction:
-		kbFunc = kbCall.(*lua.LFunction)
-
-	case lua.LTTable:
-		t := kbCall.(*lua.LTable)
-		f, ok := t.RawGet(lua.LString("call")).(*lua.LFunction)
-		if !ok {
-			log.Printf("invalid key binding definition: %s %s", a.mode, kb)
-			return
-		}
-		kbFunc = f
-
-	default:
-		// Check if there is a key binding specific for the widget
-		kbWidget, ok := a.focusedObject.(KeyBinder)
-		if !ok {
-			log.Printf("key binding not found: %s %s", a.mode, kb)
-			return
-		}
-
-		kbModeTable, ok := keyBindingsTable.RawGet(lua.LString(kbWidget.WidgetName())).(*lua.LTable)
-		if !ok {
-			log.Printf("key binding table not available for mode %s or widget %s", a.mode, kbWidget.WidgetName())
-			return
-		}
-
-		kbWidgetCall := kbModeTable.RawGet(lua.LString(kb))
-		switch kbWidgetCall.Type() {
-		case lua.LTFunction:
-			kbFunc = kbWidgetCall.(*lua.LFunction)
+	for _, kbTable := range a.keyBindingTables(keyBindingsTable, a.mode) {
+		switch kbCall := kbTable.RawGet(lua.LString(kb)).(type) {
+		case *lua.LFunction:
+			kbFunc = kbCall
 
-		case lua.LTTable:
-			t := kbWidgetCall.(*lua.LTable)
-			f, ok := t.RawGet(lua.LString("call")).(*lua.LFunction)
+		case *lua.LTable:
+			f, ok := kbCall.RawGet(lua.LString("call")).(*lua.LFunction)
 			if !ok {
-				log.Printf("invalid key binding definition: %s %s", a.mode, kb)
+				a.logErrorf("invalid key binding definition: %s %s", a.mode, kb)
 				return
 			}
 			kbFunc = f
+		}
 
-		default:
-			log.Printf("key binding not found: %s %s", a.mode, kb)
-			return
+		if kbFunc != nil {
+			break
 		}
 	}
 
+	if kbFunc == nil {
+		a.logErrorf("key binding not found: %s %s", a.mode, kb)
+		return
+	}
+
+	args := []lua.LValue{}
+	if count > 0 {
+		args = append(args, lua.LNumber(count))
+	}
+
 	if err := a.l.CallByParam(lua.P{
 		Fn:      kbFunc,
 		NRet:    0,
 		Protect: true,
-	}); err != nil {
+	}, args...); err != nil {
 		a.ToastError(fmt.Sprintf("ERROR: %v", err))
 		return
 	}
 }
 
+// keyBindingTables returns the tables where the key bindings of mode are
+// looked up, by order of precedence: the bindings of the focused widget for
+// mode, the bindings of mode, and the bindings of the focused widget for every
+// mode.
+func (a *App) keyBindingTables(keyBindingsTable *lua.LTable, mode Mode) []*lua.LTable {
+	tables := []*lua.LTable{}
+
+	var widgetTable *lua.LTable
+	if kbWidget, ok := a.focusedObject.(KeyBinder); ok {
+		widgetTable, _ = keyBindingsTable.RawGet(lua.LString(kbWidget.WidgetName())).(*lua.LTable)
+	}
+
+	if widgetTable != nil {
+		if t, ok := widgetTable.RawGet(lua.LString(mode.String())).(*lua.LTable); ok && isKeyBindingsTable(t) {
+			tables = append(tables, t)
+		}
+	}
+
+	if t, ok := keyBindingsTable.RawGet(lua.LString(mode.String())).(*lua.LTable); ok {
+		tables = append(tables, t)
+	}
+
+	if widgetTable != nil {
+		tables = append(tables, widgetTable)
+	}
+
+	return tables
+}
+
+// isKeyBindingsTable returns true if t is a table of key bindings rather than
+// a key binding with a description.
+func isKeyBindingsTable(t *lua.LTable) bool {
+	return t.RawGet(lua.LString("call")) == lua.LNil
+}
+
+// widgetModeKeyBindings returns the key of the key bindings of a widget for
+// a single mode in App.keyBindings.
+func widgetModeKeyBindings(widgetName, mode string) string {
+	return widgetName + "." + mode
+}
+
 func (a *App) configureCanvasKeyBindings(mode Mode) {
+	a.configureWindowKeyBindings(a.window, nil, mode)
+
+	for _, dp := range a.detachedPanes {
+		a.configureWindowKeyBindings(dp.window, dp, mode)
+	}
+}
+
+// configureWindowKeyBindings sets the key bindings of mode as shortcuts of
+// the window canvas. dp is the detached pane shown in the window, or nil for
+// the main window.
+func (a *App) configureWindowKeyBindings(window fyne.Window, dp *DetachedPane, mode Mode) {
 	// Remove current shortcuts
-	for _, mode := range []Mode{ModeNormal, ModeCommand, ModeSearch, ModeHelp} {
-		for _, kb := range a.keyBindings[mode.String()] {
-			var mod fyne.KeyModifier
-			keys := strings.Fields(strings.ToLower(kb))
-			for _, k := range keys {
-				switch k {
-				case "ctrl":
-					mod |= fyne.KeyModifierControl
-				case "shift":
-					mod |= fyne.KeyModifierShift
-				case "alt":
-					mod |= fyne.KeyModifierAlt
-				case "super":
-					mod |= fyne.KeyModifierSuper
+	for _, kbs := range a.keyBindings {
+		for _, kb := range kbs {
+			for _, chord := range splitChords(normalizeKeySequence(kb, a.leaderKey)) {
+				if shortcut, ok := chordShortcut(chord); ok {
+					window.Canvas().RemoveShortcut(shortcut)
 				}
 			}
-
-			keyName := keys[len(keys)-1]
-
-			shortcut := &desktop.CustomShortcut{KeyName: fyne.KeyName(strings.ToUpper(keyName)), Modifier: mod}
-			a.window.Canvas().RemoveShortcut(shortcut)
 		}
 	}
 
 	// configure new shortcuts
-	singleKeyKeyBindings := map[string]bool{}
-OUTER:
+	canvasKeyBindings := NewKeyBindings(
+		a.keyBindings[mode.String()],
+		a.leaderKey,
+		a.keySequence,
+		a.windowKeyBindingHandler(dp),
+	)
+	if modeAcceptsCounts(mode) {
+		canvasKeyBindings.WithCounts()
+	}
+
 	for _, kb := range a.keyBindings[mode.String()] {
-		var mod fyne.KeyModifier
-		keys := strings.Fields(strings.ToLower(kb))
-
-		if len(keys) == 1 {
-			singleKeyKeyBindings[strings.ToLower(keys[0])] = true
-		}
-
-		for _, k := range keys[:len(keys)-1] {
-			switch k {
-			case "ctrl":
-				mod |= fyne.KeyModifierControl
-			case "shift":
-				mod |= fyne.KeyModifierShift
-			case "alt":
-				mod |= fyne.KeyModifierAlt
-			case "super":
-				mod |= fyne.KeyModifierSuper
-			default:
-				log.Printf("WARNING: invalid key binding: '%s'", kb)
-				continue OUTER
+		for _, chord := range splitChords(normalizeKeySequence(kb, a.leaderKey)) {
+			if shortcut, ok := chordShortcut(chord); ok {
+				window.Canvas().AddShortcut(shortcut, func(shortcut fyne.Shortcut) {
+					canvasKeyBindings.OnChord(chord)
+				})
 			}
 		}
+	}
+
+	window.Canvas().SetOnTypedKey(func(k *fyne.KeyEvent) {
+		canvasKeyBindings.OnTypedKey(k)
+	})
 
-		keyName := keys[len(keys)-1]
+	window.Canvas().SetOnTypedRune(func(r rune) {
+		canvasKeyBindings.OnTypedRune(r)
+	})
+}
 
-		shortcut := &desktop.CustomShortcut{KeyName: fyne.KeyName(strings.ToUpper(keyName)), Modifier: mod}
-		a.window.Canvas().AddShortcut(shortcut, func(shortcut fyne.Shortcut) {
-			a.executeKeyBinding(kb)
-		})
+// windowKeyBindingHandler returns a function that executes key bindings
+// typed in the window showing dp, or in the main window if dp is nil. The
+// window becomes the active one before the key binding is executed, so the
+// key binding acts on the object focused there.
+func (a *App) windowKeyBindingHandler(dp *DetachedPane) func(string, int) {
+	return func(kb string, count int) {
+		a.windowActivate(dp)
+		a.executeKeyBinding(kb, count)
 	}
+}
 
-	a.window.Canvas().SetOnTypedKey(func(k *fyne.KeyEvent) {
-		kb := strings.ToLower(string(k.Name))
-		if ok := singleKeyKeyBindings[kb]; ok {
-			a.executeKeyBinding(kb)
-		}
-	})
+// modeAcceptsCounts returns true if digits typed in mode are counts for the
+// following key binding rather than text.
+func modeAcceptsCounts(mode Mode) bool {
+	return mode != ModeHelp && mode != ModeInsert && !mode.Entry()
 }
 
 func (a *App) applyKeyBindings(mode Mode) {
@@ -1081,6 +2669,8 @@ func (a *App) applyKeyBindings(mode Mode) {
 	if len(activeKeyBindings) > 0 {
 		a.commandEntry.SetKeyBindings(NewKeyBindings(
 			activeKeyBindings,
+			a.leaderKey,
+			a.keySequence,
 			a.executeKeyBinding,
 		))
 	}
@@ -1091,20 +2681,39 @@ func (a *App) applyKeyBindings(mode Mode) {
 }
 
 func (a *App) applyKeyBindingsToFocusedObject(mode Mode) {
-	activeKeyBindings := a.keyBindings[mode.String()]
-	if widg, ok := a.focusedObject.(KeyBinder); ok {
-		if kbs, ok := a.keyBindings[widg.WidgetName()]; ok {
-			activeKeyBindings = append(activeKeyBindings, kbs...)
-		}
+	if len(a.tabs) > 0 {
+		a.applyKeyBindingsToObject(mode, a.tabs[a.currentTabIndex].focusedPane(), nil)
+	}
+
+	for _, dp := range a.detachedPanes {
+		a.applyKeyBindingsToObject(mode, dp.object, dp)
+	}
+}
+
+func (a *App) applyKeyBindingsToObject(mode Mode, o fyne.CanvasObject, dp *DetachedPane) {
+	if widg, ok := o.(KeyBinder); ok {
+		activeKeyBindings := slices.Concat(
+			a.keyBindings[mode.String()],
+			a.keyBindings[widg.WidgetName()],
+			a.keyBindings[widgetModeKeyBindings(widg.WidgetName(), mode.String())],
+		)
 
-		widg.SetKeyBindings(NewKeyBindings(
+		kbs := NewKeyBindings(
 			activeKeyBindings,
-			a.executeKeyBinding,
-		))
+			a.leaderKey,
+			a.keySequence,
+			a.windowKeyBindingHandler(dp),
+		)
+		if modeAcceptsCounts(mode) {
+			kbs.WithCounts()
+		}
+
+		widg.SetKeyBindings(kbs)
 	}
 }
 
-func toDescCallTable(l *lua.LState, desc string, f *lua.LFunction) *lua.LTable {
+func (a *App) toDescCallTable(desc string, f *lua.LFunction) *lua.LTable {
+	l := a.l
 	descCallableTable := l.NewTable()
 	l.SetField(descCallableTable, "desc", lua.LString(desc))
 	l.SetField(descCallableTable, "call", f)
@@ -1118,12 +2727,17 @@ func toDescCallTable(l *lua.LState, desc string, f *lua.LFunction) *lua.LTable {
 
 			callFunc := self.RawGet(lua.LString("call")).(*lua.LFunction)
 
+			args := []lua.LValue{}
+			for i := 2; i <= l.GetTop(); i++ {
+				args = append(args, l.Get(i))
+			}
+
 			if err := l.CallByParam(lua.P{
 				Fn:      callFunc,
 				NRet:    0,
 				Protect: true,
-			}); err != nil {
-				log.Printf("ERROR: %v", err)
+			}, args...); err != nil {
+				a.logErrorf("%v", err)
 			}
 
 			return 0
@@ -0,0 +1,29 @@
+package efinui
+
+import "fyne.io/fyne/v2"
+
+// closedStackSize is the maximum number of closed panes and tabs that can be
+// restored.
+const closedStackSize = 20
+
+type closedPane struct {
+	tab      *MultiSplit
+	object   fyne.CanvasObject
+	position PanePosition
+}
+
+type closedTab struct {
+	tab   *MultiSplit
+	index int
+}
+
+// pushBounded appends v to stack, dropping the oldest elements so the stack
+// never grows beyond closedStackSize.
+func pushBounded[T any](stack []T, v T) []T {
+	stack = append(stack, v)
+	if len(stack) > closedStackSize {
+		stack = stack[len(stack)-closedStackSize:]
+	}
+
+	return stack
+}
@@ -2,6 +2,8 @@ package efinui
 
 import (
 	"slices"
+	"strings"
+	"unicode/utf8"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/widget"
@@ -15,14 +17,44 @@ type CommandEntry struct {
 	mode      Mode
 	OnCommand func(string)
 	OnSearch  func(string)
+	OnSQL     func(string)
 
-	history    []string
-	historyPos int
+	// OnModeSubmit is called with the text submitted in custom modes.
+	OnModeSubmit func(Mode, string)
+
+	// histories holds the history of the text submitted in every mode.
+	histories map[Mode]*entryHistory
+
+	// Complete returns the completions of text typed in mode.
+	Complete func(mode Mode, text string) []Completion
+
+	// OnCompletionsChange is called with the candidates of the current
+	// completion and the index of the selected one, or with no candidates
+	// when the completion ends.
+	OnCompletionsChange func(completions []Completion, index int)
+
+	completions     []Completion
+	completionIndex int
+
+	// OnHistorySearchChange is called with the history entries matching the
+	// text typed during a history search and the index of the selected one,
+	// or with nil matches when the search ends.
+	OnHistorySearchChange func(matches []string, index int)
+
+	historySearch *historySearch
+}
+
+// Completion is a candidate of a completion. Text is the whole text of the
+// entry once completed, and Label the completed word.
+type Completion struct {
+	Text  string
+	Label string
 }
 
 func NewCommandEntry() *CommandEntry {
 	e := &CommandEntry{
-		mode: ModeNormal,
+		mode:      ModeNormal,
+		histories: map[Mode]*entryHistory{},
 	}
 	e.ExtendBaseWidget(e)
 
@@ -34,6 +66,18 @@ func NewCommandEntry() *CommandEntry {
 
 			case ModeSearch:
 				e.OnSearch(text)
+
+			case ModeSQL:
+				e.OnSQL(text)
+
+			case ModeHelp:
+				// The text is the filter of the help dialog, which is kept
+				return
+
+			default:
+				if e.OnModeSubmit != nil {
+					e.OnModeSubmit(e.mode, text)
+				}
 			}
 
 			e.SetText("")
@@ -45,11 +89,13 @@ func NewCommandEntry() *CommandEntry {
 
 func (ce *CommandEntry) SetMode(mode Mode) {
 	ce.mode = mode
+	ce.completionsReset()
+	ce.historySearchStop()
 
-	if mode == ModeNormal || mode == ModeHelp {
-		ce.Disable()
-	} else {
+	if mode.Entry() {
 		ce.Enable()
+	} else {
+		ce.Disable()
 	}
 
 	ce.Refresh()
@@ -64,7 +110,82 @@ func (ce *CommandEntry) TypedKey(ev *fyne.KeyEvent) {
 		return
 	}
 
+	if ce.historySearch != nil {
+		switch ev.Name {
+		case fyne.KeyReturn, fyne.KeyEnter:
+			// The selected match is submitted below
+			ce.historySearchAccept()
+
+		case fyne.KeyTab:
+			// The selected match can be edited before being submitted
+			ce.historySearchAccept()
+			return
+		}
+	}
+
+	if ev.Name == fyne.KeyTab {
+		ce.CompleteNext()
+		return
+	}
+
+	ce.completionsReset()
+
+	text := ce.Text
 	ce.Entry.TypedKey(ev)
+	if ce.Text != text {
+		ce.historySearchUpdate()
+	}
+}
+
+func (ce *CommandEntry) TypedRune(r rune) {
+	ce.completionsReset()
+	ce.Entry.TypedRune(r)
+	ce.historySearchUpdate()
+}
+
+// AcceptsTab makes tab complete the text instead of moving the focus.
+func (ce *CommandEntry) AcceptsTab() bool {
+	return true
+}
+
+// CompleteNext replaces the text with its next completion. Completing again
+// without typing cycles through all of them.
+func (ce *CommandEntry) CompleteNext() {
+	if ce.completions == nil {
+		if ce.Complete == nil {
+			return
+		}
+
+		ce.completions = ce.Complete(ce.mode, ce.Text)
+		ce.completionIndex = -1
+	}
+
+	if len(ce.completions) == 0 {
+		return
+	}
+
+	ce.completionIndex = (ce.completionIndex + 1) % len(ce.completions)
+
+	text := ce.completions[ce.completionIndex].Text
+	ce.SetText(text)
+	ce.CursorColumn = utf8.RuneCountInString(text)
+	ce.Refresh()
+
+	if ce.OnCompletionsChange != nil {
+		ce.OnCompletionsChange(ce.completions, ce.completionIndex)
+	}
+}
+
+func (ce *CommandEntry) completionsReset() {
+	if ce.completions == nil {
+		return
+	}
+
+	ce.completions = nil
+
+	if ce.OnCompletionsChange != nil {
+		ce.OnCompletionsChange(nil, -1)
+	}
 }
 
 func (ce *CommandEntry) TypedShortcut(sc fyne.Shortcut) {
@@ -72,29 +193,49 @@ func (ce *CommandEntry) TypedShortcut(sc fyne.Shortcut) {
 		return
 	}
 
+	text := ce.Text
 	ce.Entry.TypedShortcut(sc)
+	if ce.Text != text {
+		ce.historySearchUpdate()
+	}
+}
+
+type entryHistory struct {
+	entries []string
+	pos     int
 }
 
-func (ce *CommandEntry) SetHistory(history []string) {
-	ce.history = []string{}
+// SetHistory sets the history of the text submitted in mode, from the oldest
+// to the newest entry.
+func (ce *CommandEntry) SetHistory(mode Mode, history []string) {
+	h := &entryHistory{}
 
 	present := map[string]bool{}
 	for _, cmd := range slices.Backward(history) {
 		if _, ok := present[cmd]; !ok {
-			ce.history = append(ce.history, cmd)
+			h.entries = append(h.entries, cmd)
 			present[cmd] = true
 		}
 	}
 
-	slices.Reverse(ce.history)
-	ce.historyPos = len(ce.history)
+	slices.Reverse(h.entries)
+	h.pos = len(h.entries)
+
+	ce.histories[mode] = h
 }
 
 func (ce *CommandEntry) HistoryNext() {
-	ce.historyPos = min(len(ce.history), ce.historyPos+1)
+	ce.historySearchStop()
+
+	h, ok := ce.histories[ce.mode]
+	if !ok {
+		return
+	}
+
+	h.pos = min(len(h.entries), h.pos+1)
 
-	if ce.historyPos < len(ce.history) {
-		ce.SetText(ce.history[ce.historyPos])
+	if h.pos < len(h.entries) {
+		ce.SetText(h.entries[h.pos])
 	} else {
 		ce.SetText("")
 	}
@@ -102,11 +243,98 @@ func (ce *CommandEntry) HistoryNext() {
 }
 
 func (ce *CommandEntry) HistoryPrev() {
-	if len(ce.history) == 0 {
+	ce.historySearchStop()
+
+	h, ok := ce.histories[ce.mode]
+	if !ok || len(h.entries) == 0 {
 		return
 	}
 
-	ce.historyPos = max(0, ce.historyPos-1)
-	ce.SetText(ce.history[ce.historyPos])
+	h.pos = max(0, h.pos-1)
+	ce.SetText(h.entries[h.pos])
 	ce.Refresh()
 }
+
+// historySearch holds the history entries matching the text typed, from the
+// newest to the oldest, and the index of the selected one.
+type historySearch struct {
+	matches []string
+	index   int
+}
+
+// HistorySearch starts an incremental search in the history of the current
+// mode, where the text typed filters the entries. Calling it again during
+// the search selects the next older match. Submitting runs the selected
+// match, and tab puts it in the entry to edit it.
+func (ce *CommandEntry) HistorySearch() {
+	if ce.historySearch == nil {
+		ce.completionsReset()
+		ce.historySearch = &historySearch{}
+		ce.historySearchUpdate()
+		return
+	}
+
+	hs := ce.historySearch
+	if len(hs.matches) == 0 {
+		return
+	}
+
+	hs.index = (hs.index + 1) % len(hs.matches)
+	ce.historySearchChanged()
+}
+
+func (ce *CommandEntry) historySearchUpdate() {
+	hs := ce.historySearch
+	if hs == nil {
+		return
+	}
+
+	hs.matches = []string{}
+	hs.index = 0
+
+	if h, ok := ce.histories[ce.mode]; ok {
+		text := strings.ToLower(ce.Text)
+		for _, entry := range slices.Backward(h.entries) {
+			if strings.Contains(strings.ToLower(entry), text) {
+				hs.matches = append(hs.matches, entry)
+			}
+		}
+	}
+
+	ce.historySearchChanged()
+}
+
+// historySearchAccept ends the search with the selected match as text.
+func (ce *CommandEntry) historySearchAccept() {
+	hs := ce.historySearch
+	if hs == nil {
+		return
+	}
+
+	ce.historySearchStop()
+
+	if len(hs.matches) > 0 {
+		text := hs.matches[hs.index]
+		ce.SetText(text)
+		ce.CursorColumn = utf8.RuneCountInString(text)
+		ce.Refresh()
+	}
+}
+
+func (ce *CommandEntry) historySearchStop() {
+	if ce.historySearch == nil {
+		return
+	}
+
+	ce.historySearch = nil
+
+	if ce.OnHistorySearchChange != nil {
+		ce.OnHistorySearchChange(nil, -1)
+	}
+}
+
+func (ce *CommandEntry) historySearchChanged() {
+	if ce.OnHistorySearchChange != nil {
+		ce.OnHistorySearchChange(ce.historySearch.matches, ce.historySearch.index)
+	}
+}
@@ -0,0 +1,140 @@
+package efinui
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+// complete returns the completions of the word at the end of text typed in
+// mode.
+func (a *App) complete(mode Mode, text string) []Completion {
+	switch mode {
+	case ModeCommand:
+		return a.completeCommand(text)
+	case ModeSQL:
+		return a.completeSQL(text)
+	}
+
+	return nil
+}
+
+// completeCommand completes user command names at the beginning of text, and
+// Lua global names, or fields of Lua tables as in "themes.syn", anywhere.
+func (a *App) completeCommand(text string) []Completion {
+	start := wordStart(text, func(r rune) bool {
+		return r == '_' || r == '.' || unicode.IsLetter(r) || unicode.IsDigit(r)
+	})
+	prefix, word := text[:start], text[start:]
+
+	candidates := []string{}
+	if strings.TrimSpace(prefix) == "" {
+		for _, name := range a.userCommandNames(word) {
+			candidates = append(candidates, name+" ")
+		}
+	}
+
+	return completions(prefix, append(candidates, a.luaNames(word)...))
+}
+
+// luaNames returns the names of the Lua globals starting with word, or the
+// names of the fields of a table if word is a path like "settings.ke".
+func (a *App) luaNames(word string) []string {
+	path := strings.Split(word, ".")
+
+	table := a.l.G.Global
+	for _, name := range path[:len(path)-1] {
+		t, ok := table.RawGetString(name).(*lua.LTable)
+		if !ok {
+			return nil
+		}
+		table = t
+	}
+
+	fieldPrefix := strings.Join(path[:len(path)-1], ".")
+	if fieldPrefix != "" {
+		fieldPrefix += "."
+	}
+
+	names := []string{}
+	table.ForEach(func(k lua.LValue, _ lua.LValue) {
+		name, ok := k.(lua.LString)
+		if ok && strings.HasPrefix(string(name), path[len(path)-1]) && isIdentifier(string(name)) {
+			names = append(names, fieldPrefix+string(name))
+		}
+	})
+
+	slices.Sort(names)
+
+	return names
+}
+
+// completeSQL completes table and column names of the database.
+func (a *App) completeSQL(text string) []Completion {
+	start := wordStart(text, func(r rune) bool {
+		return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+	})
+	prefix, word := text[:start], text[start:]
+
+	names, err := schemaNames(a.db)
+	if err != nil {
+		a.logErrorf("could not read database schema: %v", err)
+		return nil
+	}
+
+	candidates := []string{}
+	for _, name := range names {
+		if strings.HasPrefix(strings.ToLower(name), strings.ToLower(word)) {
+			candidates = append(candidates, name)
+		}
+	}
+
+	slices.Sort(candidates)
+
+	return completions(prefix, candidates)
+}
+
+// completions returns a completion for every distinct candidate, appended to
+// prefix, in the order of candidates.
+func completions(prefix string, candidates []string) []Completion {
+	result := []Completion{}
+	seen := map[string]bool{}
+	for _, c := range candidates {
+		if seen[c] {
+			continue
+		}
+		seen[c] = true
+
+		result = append(result, Completion{
+			Text:  prefix + c,
+			Label: strings.TrimSpace(c),
+		})
+	}
+
+	return result
+}
+
+// wordStart returns the index in text where the word at its end starts,
+// being the characters of words those for which isWordRune returns true.
+func wordStart(text string, isWordRune func(rune) bool) int {
+	runes := []rune(text)
+
+	i := len(runes)
+	for i > 0 && isWordRune(runes[i-1]) {
+		i--
+	}
+
+	return len(string(runes[:i]))
+}
+
+func isIdentifier(s string) bool {
+	for i, r := range s {
+		if r != '_' && !unicode.IsLetter(r) && (i == 0 || !unicode.IsDigit(r)) {
+			return false
+		}
+	}
+
+	return s != ""
+}
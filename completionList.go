@@ -0,0 +1,96 @@
+package efinui
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+const completionListMaxItems = 10
+
+// CompletionList shows the candidates of a completion above the command
+// entry and highlights the selected one.
+type CompletionList struct {
+	widget.BaseWidget
+
+	labels        []string
+	selectedIndex int
+
+	content *fyne.Container
+}
+
+func NewCompletionList() *CompletionList {
+	cl := &CompletionList{
+		content: container.NewVBox(),
+	}
+
+	cl.ExtendBaseWidget(cl)
+	cl.Hide()
+
+	return cl
+}
+
+func (cl *CompletionList) CreateRenderer() fyne.WidgetRenderer {
+	return widget.NewSimpleRenderer(container.NewStack(
+		canvas.NewRectangle(cl.Theme().Color(theme.ColorNameOverlayBackground, theme.VariantDark)),
+		cl.content,
+	))
+}
+
+// SetCompletions shows labels with the one at selectedIndex highlighted. The
+// list is hidden if there are less than two labels.
+func (cl *CompletionList) SetCompletions(labels []string, selectedIndex int) {
+	if len(labels) < 2 {
+		labels = nil
+	}
+
+	cl.SetLabels(labels, selectedIndex)
+}
+
+// SetLabels shows labels with the one at selectedIndex highlighted, or hides
+// the list if there are no labels.
+func (cl *CompletionList) SetLabels(labels []string, selectedIndex int) {
+	cl.labels = labels
+	cl.selectedIndex = selectedIndex
+
+	if len(labels) == 0 {
+		cl.Hide()
+		return
+	}
+
+	cl.updateContent()
+	cl.Show()
+}
+
+func (cl *CompletionList) updateContent() {
+	selectedColor := cl.Theme().Color(theme.ColorNameSelection, theme.VariantDark)
+
+	// Show a window of the labels around the selected one
+	first := 0
+	if cl.selectedIndex >= completionListMaxItems {
+		first = cl.selectedIndex - completionListMaxItems + 1
+	}
+	last := min(len(cl.labels), first+completionListMaxItems)
+
+	objects := []fyne.CanvasObject{}
+	for i := first; i < last; i++ {
+		label := widget.NewLabel(cl.labels[i])
+
+		if i == cl.selectedIndex {
+			label.TextStyle = fyne.TextStyle{Bold: true}
+			objects = append(objects, container.NewStack(canvas.NewRectangle(selectedColor), label))
+		} else {
+			objects = append(objects, label)
+		}
+	}
+
+	cl.content.Objects = objects
+	cl.content.Refresh()
+}
+
+func (cl *CompletionList) Refresh() {
+	cl.updateContent()
+	cl.BaseWidget.Refresh()
+}
@@ -0,0 +1,140 @@
+package efinui
+
+import (
+	"fmt"
+	"slices"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// DetachedPane is a pane moved out of its tab into a window of its own. It
+// remembers where it came from, so it can be attached back to the same place.
+type DetachedPane struct {
+	window fyne.Window
+	object fyne.CanvasObject
+
+	tab      *MultiSplit
+	position PanePosition
+}
+
+// PaneDetach moves the focused pane of the current tab into a new window.
+func (a *App) PaneDetach() error {
+	if a.activeDetachedPane != nil {
+		return fmt.Errorf("pane is already detached")
+	}
+
+	tab := a.tabs[a.currentTabIndex]
+
+	o, position := tab.PaneRemove()
+	if o == nil {
+		return fmt.Errorf("no pane to detach")
+	}
+
+	title := "Efin"
+	if tab.Name() != "" {
+		title = fmt.Sprintf("Efin - %s", tab.Name())
+	}
+
+	dp := &DetachedPane{
+		window:   a.fyneApp.NewWindow(title),
+		object:   o,
+		tab:      tab,
+		position: position,
+	}
+	dp.window.SetContent(container.NewPadded(o))
+	dp.window.SetCloseIntercept(func() {
+		a.paneAttach(dp)
+	})
+	dp.window.Resize(a.window.Canvas().Size())
+
+	a.detachedPanes = append(a.detachedPanes, dp)
+
+	a.configureWindowKeyBindings(dp.window, dp, a.mode)
+	a.windowActivate(dp)
+
+	dp.window.Show()
+	a.focusFocusedObject()
+
+	return nil
+}
+
+// PaneAttach moves the pane of the active detached window back into the tab
+// it was detached from. If the main window is active, the most recently
+// detached pane is attached.
+func (a *App) PaneAttach() error {
+	dp := a.activeDetachedPane
+	if dp == nil {
+		if len(a.detachedPanes) == 0 {
+			return fmt.Errorf("no detached panes")
+		}
+
+		dp = a.detachedPanes[len(a.detachedPanes)-1]
+	}
+
+	a.paneAttach(dp)
+
+	return nil
+}
+
+func (a *App) paneAttach(dp *DetachedPane) {
+	a.detachedPaneClose(dp)
+
+	if i := slices.Index(a.tabs, dp.tab); i >= 0 {
+		a.tabSwitch(i)
+		dp.tab.PaneRestore(dp.object, dp.position)
+	} else {
+		a.tabs[a.currentTabIndex].PaneCreate(dp.object)
+	}
+}
+
+// detachedPaneClose closes the window of dp without destroying its pane.
+func (a *App) detachedPaneClose(dp *DetachedPane) {
+	a.detachedPanes = slices.DeleteFunc(a.detachedPanes, func(d *DetachedPane) bool {
+		return d == dp
+	})
+
+	if a.activeDetachedPane == dp {
+		a.windowActivate(nil)
+	}
+
+	// Release the pane from the window canvas before closing it, so it can be
+	// shown somewhere else
+	dp.window.SetContent(widget.NewLabel(""))
+	dp.window.Close()
+
+	a.window.RequestFocus()
+	a.focusFocusedObject()
+}
+
+func (a *App) activeWindow() fyne.Window {
+	if a.activeDetachedPane != nil {
+		return a.activeDetachedPane.window
+	}
+
+	return a.window
+}
+
+// windowActivate makes the window showing dp the one key bindings act on, or
+// the main window if dp is nil.
+func (a *App) windowActivate(dp *DetachedPane) {
+	if dp == a.activeDetachedPane {
+		return
+	}
+
+	a.activeDetachedPane = dp
+
+	if dp != nil {
+		a.focusedObject = dp.object
+	} else {
+		a.focusedObject = a.tabs[a.currentTabIndex].focusedPane()
+	}
+
+	a.modeCheckFocus()
+
+	a.updateHelpDialog()
+	a.updateStatusLine()
+
+	a.focusChangedEmit()
+}
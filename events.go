@@ -0,0 +1,79 @@
+package efinui
+
+import (
+	"fmt"
+	"slices"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+const (
+	EventStartup       = "startup"
+	EventExit          = "exit"
+	EventModeChanged   = "mode_changed"
+	EventFocusChanged  = "focus_changed"
+	EventQueryFinished = "query_finished"
+	EventRequestOpened = "request_opened"
+	EventTabCreated    = "tab_created"
+)
+
+var events = []string{
+	EventStartup,
+	EventExit,
+	EventModeChanged,
+	EventFocusChanged,
+	EventQueryFinished,
+	EventRequestOpened,
+	EventTabCreated,
+}
+
+// eventOn registers fn to be called every time event is emitted.
+func (a *App) eventOn(event string, fn *lua.LFunction) error {
+	if !slices.Contains(events, event) {
+		return fmt.Errorf("unknown event %q", event)
+	}
+
+	a.eventHandlers[event] = append(a.eventHandlers[event], fn)
+
+	return nil
+}
+
+// eventEmit calls the functions registered for event with a table holding
+// the event data.
+func (a *App) eventEmit(event string, data map[string]lua.LValue) {
+	if len(a.eventHandlers[event]) == 0 {
+		return
+	}
+
+	t := a.l.NewTable()
+	a.l.SetField(t, "event", lua.LString(event))
+	for k, v := range data {
+		a.l.SetField(t, k, v)
+	}
+
+	for _, fn := range a.eventHandlers[event] {
+		a.luaHookCall(fn, t)
+	}
+}
+
+// requestTable returns a Lua table with the fields of req.
+func requestTable(l *lua.LState, req *Request) *lua.LTable {
+	t := l.NewTable()
+	l.SetField(t, "id", lua.LString(req.ID))
+	l.SetField(t, "method", lua.LString(req.Method))
+	l.SetField(t, "host", lua.LString(req.Host))
+	l.SetField(t, "url", lua.LString(req.URL))
+	l.SetField(t, "timestamp", lua.LString(req.Timestamp))
+	l.SetField(t, "body", lua.LString(req.Body))
+
+	headers := l.NewTable()
+	for _, h := range req.Headers {
+		header := l.NewTable()
+		l.SetField(header, "name", lua.LString(h.Name))
+		l.SetField(header, "value", lua.LString(h.Value))
+		headers.Append(header)
+	}
+	l.SetField(t, "headers", headers)
+
+	return t
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"image/color"
 	"slices"
+	"strings"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/canvas"
@@ -12,24 +13,34 @@ import (
 	"fyne.io/fyne/v2/widget"
 )
 
+// HelpSection is a group of key bindings shown in the help dialog, like the
+// ones of a mode or of a widget.
+type HelpSection struct {
+	Title        string
+	Descriptions map[string]string
+}
+
 type HelpDialog struct {
 	widget.BaseWidget
 
 	content    *fyne.Container
 	background *canvas.Rectangle
+	scroll     *container.Scroll
 	container  *fyne.Container
 
-	descriptions map[string]string
+	sections []HelpSection
+	filter   string
 }
 
-func NewHelpDialog(descriptions map[string]string) *HelpDialog {
-	hd := &HelpDialog{
-		descriptions: descriptions,
-	}
+func NewHelpDialog() *HelpDialog {
+	hd := &HelpDialog{}
 
 	hd.content = container.NewVBox(widget.NewLabel("Help"))
 	hd.background = canvas.NewRectangle(color.RGBA{R: 255, A: 255})
-	hd.container = container.NewCenter(container.NewStack(hd.background, hd.content))
+
+	hd.scroll = container.NewVScroll(hd.content)
+	hd.scroll.SetMinSize(fyne.NewSize(600, 450))
+	hd.container = container.NewCenter(container.NewStack(hd.background, hd.scroll))
 
 	hd.ExtendBaseWidget(hd)
 
@@ -43,27 +54,89 @@ func (hd *HelpDialog) CreateRenderer() fyne.WidgetRenderer {
 	return widget.NewSimpleRenderer(hd.container)
 }
 
-func (hd *HelpDialog) SetDescriptions(descriptions map[string]string) {
-	hd.descriptions = descriptions
+func (hd *HelpDialog) SetSections(sections []HelpSection) {
+	hd.sections = sections
+	hd.updateContent()
+}
+
+// SetFilter shows only the key bindings whose key or description contains
+// filter, ignoring case.
+func (hd *HelpDialog) SetFilter(filter string) {
+	hd.filter = filter
 	hd.updateContent()
 }
 
 func (hd *HelpDialog) updateContent() {
-	keyList := []string{}
-	for k, _ := range hd.descriptions {
-		keyList = append(keyList, k)
+	title := "Help"
+	if hd.filter != "" {
+		title = fmt.Sprintf("Help (filter: %s)", hd.filter)
 	}
-	slices.Sort(keyList)
 
-	descLabels := make([]fyne.CanvasObject, len(hd.descriptions)+1)
-	descLabels[0] = widget.NewLabel("Help")
+	objects := []fyne.CanvasObject{widget.NewLabel(title)}
+
+	for _, section := range hd.sections {
+		keyList := []string{}
+		for k, desc := range section.Descriptions {
+			if hd.matches(k) || hd.matches(desc) {
+				keyList = append(keyList, k)
+			}
+		}
+
+		if len(keyList) == 0 {
+			continue
+		}
+		slices.Sort(keyList)
+
+		objects = append(objects, widget.NewLabelWithStyle(section.Title, fyne.TextAlignLeading, fyne.TextStyle{Bold: true}))
+		for _, k := range keyList {
+			objects = append(objects, widget.NewLabel(fmt.Sprintf("%s: %s", k, section.Descriptions[k])))
+		}
+	}
 
-	for i, k := range keyList {
-		descLabels[i+1] = widget.NewLabel(fmt.Sprintf("%s: %s", k, hd.descriptions[k]))
+	if len(objects) == 1 {
+		objects = append(objects, widget.NewLabel("No key bindings found"))
 	}
 
-	hd.content.Objects = descLabels
+	hd.content.Objects = objects
 	hd.content.Refresh()
+	hd.scroll.ScrollToTop()
+}
+
+func (hd *HelpDialog) ScrollUp() {
+	hd.scrollBy(-hd.lineHeight())
+}
+
+func (hd *HelpDialog) ScrollDown() {
+	hd.scrollBy(hd.lineHeight())
+}
+
+func (hd *HelpDialog) ScrollPageUp() {
+	hd.scrollBy(-hd.scroll.Size().Height)
+}
+
+func (hd *HelpDialog) ScrollPageDown() {
+	hd.scrollBy(hd.scroll.Size().Height)
+}
+
+// scrollBy scrolls the key bindings dy down, or up if dy is negative. The
+// scroll container keeps the offset within the content.
+func (hd *HelpDialog) scrollBy(dy float32) {
+	offset := hd.scroll.Offset
+	offset.Y = max(0, offset.Y+dy)
+
+	hd.scroll.ScrollToOffset(offset)
+}
+
+// lineHeight returns the height of a label listing a key binding.
+func (hd *HelpDialog) lineHeight() float32 {
+	th := hd.Theme()
+	textHeight := fyne.MeasureText("M", th.Size(theme.SizeNameText), fyne.TextStyle{}).Height
+
+	return textHeight + 2*th.Size(theme.SizeNameInnerPadding)
+}
+
+func (hd *HelpDialog) matches(s string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(hd.filter))
 }
 
 func (hd *HelpDialog) Refresh() {
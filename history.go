@@ -0,0 +1,208 @@
+package efinui
+
+import (
+	"encoding/json"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+const DefaultHistoryMaxSize = 1000
+
+// HistoryEntry is a text submitted in the command entry. The history file
+// holds one entry per line, encoded in JSON.
+type HistoryEntry struct {
+	// Type is the mode the text was submitted in: command, search or sql.
+	Type string `json:"type"`
+	Text string `json:"text"`
+
+	// Timestamp and Database are unknown for the entries converted from the
+	// history files of older versions.
+	Timestamp time.Time `json:"timestamp,omitzero"`
+	Database  string    `json:"database,omitempty"`
+}
+
+func (a *App) historyAppend(mode Mode, text string) error {
+	entry := HistoryEntry{
+		Type:      mode.String(),
+		Text:      text,
+		Timestamp: time.Now(),
+		Database:  a.dbPath,
+	}
+	a.history = append(a.history, entry)
+
+	// The file is compacted once it is 10% larger than its maximum size, so
+	// it is not rewritten on every new entry
+	if maxSize := a.historyMaxSize(); maxSize > 0 && len(a.history) > maxSize+maxSize/10 {
+		return a.historyCompact()
+	}
+
+	f, err := os.OpenFile(a.histFilePath, os.O_APPEND|os.O_WRONLY|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return historyEncoder(f).Encode(entry)
+}
+
+// historyTexts returns the texts submitted in mode, from the oldest to the
+// newest. The ones submitted on the current database come last, so they are
+// recalled first.
+func (a *App) historyTexts(mode Mode) []string {
+	others := []string{}
+	current := []string{}
+
+	for _, entry := range a.history {
+		if entry.Type != mode.String() {
+			continue
+		}
+
+		if entry.Database == a.dbPath {
+			current = append(current, entry.Text)
+		} else {
+			others = append(others, entry.Text)
+		}
+	}
+
+	return slices.Concat(others, current)
+}
+
+// historyMaxSize returns the maximum number of entries of the history file,
+// set by settings.history_max_size. There is no limit if it is not positive.
+func (a *App) historyMaxSize() int {
+	settingsTable, ok := a.l.GetGlobal("settings").(*lua.LTable)
+	if !ok {
+		return DefaultHistoryMaxSize
+	}
+
+	maxSize, ok := settingsTable.RawGet(lua.LString("history_max_size")).(lua.LNumber)
+	if !ok {
+		return DefaultHistoryMaxSize
+	}
+
+	return int(maxSize)
+}
+
+type historyKey struct {
+	entryType string
+	text      string
+	database  string
+}
+
+// historyCompact keeps only the newest of the entries with the same text
+// submitted on the same database, drops the oldest entries beyond the maximum
+// size and rewrites the history file.
+func (a *App) historyCompact() error {
+	maxSize := a.historyMaxSize()
+
+	present := map[historyKey]bool{}
+	compacted := []HistoryEntry{}
+	for _, entry := range slices.Backward(a.history) {
+		if maxSize > 0 && len(compacted) == maxSize {
+			break
+		}
+
+		key := historyKey{entry.Type, entry.Text, entry.Database}
+		if present[key] {
+			continue
+		}
+		present[key] = true
+
+		compacted = append(compacted, entry)
+	}
+	slices.Reverse(compacted)
+
+	a.history = compacted
+
+	return a.historyWrite()
+}
+
+// historyWrite replaces the history file with the entries in memory.
+func (a *App) historyWrite() error {
+	tmpPath := a.histFilePath + ".tmp"
+
+	f, err := os.OpenFile(tmpPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+
+	enc := historyEncoder(f)
+	for _, entry := range a.history {
+		if err := enc.Encode(entry); err != nil {
+			f.Close()
+			return err
+		}
+	}
+
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpPath, a.histFilePath)
+}
+
+func historyEncoder(f *os.File) *json.Encoder {
+	enc := json.NewEncoder(f)
+	enc.SetEscapeHTML(false)
+
+	return enc
+}
+
+// loadHistory reads the history file. Histories written by older versions,
+// with a line of command mode text per entry, are converted to the current
+// format.
+func (a *App) loadHistory() error {
+	migrated := false
+
+	entries, err := historyRead(a.histFilePath, func(line string) HistoryEntry {
+		migrated = true
+		return HistoryEntry{Type: ModeCommand.String(), Text: line}
+	})
+	if err != nil {
+		return err
+	}
+
+	a.history = entries
+
+	maxSize := a.historyMaxSize()
+	if !migrated && (maxSize <= 0 || len(a.history) <= maxSize) {
+		return nil
+	}
+
+	return a.historyCompact()
+}
+
+// historyRead returns the entries of the history file at path, or none if it
+// does not exist. Lines that are not entries encoded in JSON are converted by
+// legacyEntry.
+func historyRead(path string, legacyEntry func(line string) HistoryEntry) ([]HistoryEntry, error) {
+	fbytes, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	entries := []HistoryEntry{}
+	for line := range strings.Lines(string(fbytes)) {
+		line = strings.TrimRight(line, "\n")
+		if line == "" {
+			continue
+		}
+
+		var entry HistoryEntry
+		if err := json.Unmarshal([]byte(line), &entry); err != nil || entry.Type == "" {
+			entry = legacyEntry(line)
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,182 @@
+package efinui
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+// newHistoryTestApp returns an App with only what the history needs, with
+// settings.history_max_size set to maxSize.
+func newHistoryTestApp(t *testing.T, maxSize int) *App {
+	t.Helper()
+
+	a := &App{
+		l:            lua.NewState(),
+		histFilePath: filepath.Join(t.TempDir(), "history"),
+		dbPath:       "current.db",
+	}
+	t.Cleanup(a.l.Close)
+
+	settingsTable := a.l.NewTable()
+	a.l.SetField(settingsTable, "history_max_size", lua.LNumber(maxSize))
+	a.l.SetGlobal("settings", settingsTable)
+
+	return a
+}
+
+func historyEntryTexts(entries []HistoryEntry) []string {
+	texts := []string{}
+	for _, entry := range entries {
+		texts = append(texts, entry.Type+":"+entry.Text+"@"+entry.Database)
+	}
+
+	return texts
+}
+
+func TestHistoryCompact(t *testing.T) {
+	tests := []struct {
+		name    string
+		maxSize int
+		history []HistoryEntry
+		want    []string
+	}{
+		{
+			name:    "empty",
+			maxSize: 10,
+			history: nil,
+			want:    []string{},
+		},
+		{
+			name:    "keeps the newest duplicate",
+			maxSize: 10,
+			history: []HistoryEntry{
+				{Type: "command", Text: "a", Database: "x.db"},
+				{Type: "command", Text: "b", Database: "x.db"},
+				{Type: "command", Text: "a", Database: "x.db"},
+			},
+			want: []string{"command:b@x.db", "command:a@x.db"},
+		},
+		{
+			name:    "same text in other modes or databases",
+			maxSize: 10,
+			history: []HistoryEntry{
+				{Type: "command", Text: "a", Database: "x.db"},
+				{Type: "sql", Text: "a", Database: "x.db"},
+				{Type: "command", Text: "a", Database: "y.db"},
+			},
+			want: []string{"command:a@x.db", "sql:a@x.db", "command:a@y.db"},
+		},
+		{
+			name:    "drops the oldest beyond the maximum size",
+			maxSize: 2,
+			history: []HistoryEntry{
+				{Type: "command", Text: "a"},
+				{Type: "command", Text: "b"},
+				{Type: "command", Text: "c"},
+				{Type: "command", Text: "c"},
+			},
+			want: []string{"command:b@", "command:c@"},
+		},
+		{
+			name:    "no maximum size",
+			maxSize: 0,
+			history: []HistoryEntry{
+				{Type: "command", Text: "a"},
+				{Type: "command", Text: "b"},
+				{Type: "command", Text: "c"},
+			},
+			want: []string{"command:a@", "command:b@", "command:c@"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := newHistoryTestApp(t, tt.maxSize)
+			a.history = tt.history
+
+			if err := a.historyCompact(); err != nil {
+				t.Fatalf("historyCompact() error = %v", err)
+			}
+
+			if got := historyEntryTexts(a.history); !slices.Equal(got, tt.want) {
+				t.Errorf("history = %q, want %q", got, tt.want)
+			}
+
+			written, err := historyRead(a.histFilePath, func(line string) HistoryEntry {
+				t.Errorf("history file line %q is not an entry", line)
+				return HistoryEntry{}
+			})
+			if err != nil {
+				t.Fatalf("historyRead() error = %v", err)
+			}
+
+			if got := historyEntryTexts(written); !slices.Equal(got, tt.want) {
+				t.Errorf("history file = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadHistoryLegacy(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{
+			name:    "no history",
+			content: "",
+			want:    []string{},
+		},
+		{
+			name:    "plain lines",
+			content: "print(1)\nquery(\"x\")\n",
+			want:    []string{"command:print(1)@", "command:query(\"x\")@"},
+		},
+		{
+			name:    "plain lines after entries",
+			content: `{"type":"sql","text":"SELECT 2","database":"x.db"}` + "\nprint(2)\n",
+			want:    []string{"sql:SELECT 2@x.db", "command:print(2)@"},
+		},
+		{
+			name:    "duplicated plain lines",
+			content: "print(1)\nprint(2)\n\nprint(1)\n",
+			want:    []string{"command:print(2)@", "command:print(1)@"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := newHistoryTestApp(t, DefaultHistoryMaxSize)
+
+			if err := os.WriteFile(a.histFilePath, []byte(tt.content), 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := a.loadHistory(); err != nil {
+				t.Fatalf("loadHistory() error = %v", err)
+			}
+
+			if got := historyEntryTexts(a.history); !slices.Equal(got, tt.want) {
+				t.Errorf("history = %q, want %q", got, tt.want)
+			}
+
+			// The history is written back in the current format
+			written, err := historyRead(a.histFilePath, func(line string) HistoryEntry {
+				t.Errorf("history file line %q is not an entry", line)
+				return HistoryEntry{}
+			})
+			if err != nil {
+				t.Fatalf("historyRead() error = %v", err)
+			}
+
+			if got := historyEntryTexts(written); !slices.Equal(got, tt.want) {
+				t.Errorf("history file = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
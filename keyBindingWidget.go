@@ -1,42 +1,176 @@
 package efinui
 
 import (
+	"slices"
 	"strings"
+	"time"
+	"unicode/utf8"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/driver/desktop"
 )
 
+const (
+	DefaultKeySequenceTimeout = time.Second
+	DefaultLeaderKey          = "space"
+)
+
+// KeySequence holds the keys typed so far of a key binding made of several
+// keys, like "g g". It is shared by all the KeyBindings, so a sequence can be
+// completed even if the widget receiving the keys changes.
+type KeySequence struct {
+	// Timeout is how long to wait for the next key of a sequence. When it
+	// expires, the keys typed so far are executed if they are a key binding
+	// on their own, or discarded otherwise.
+	Timeout time.Duration
+
+	// OnPendingChange is called with the keys typed so far every time a key
+	// is added to the pending sequence or the sequence is discarded, in which
+	// case pending is empty.
+	OnPendingChange func(pending string)
+
+	pending []string
+	timer   *time.Timer
+
+	// count is the number typed before a key binding to repeat it, as in
+	// "10 j". It is zero if no count was typed.
+	count int
+}
+
+func NewKeySequence() *KeySequence {
+	return &KeySequence{
+		Timeout: DefaultKeySequenceTimeout,
+	}
+}
+
+// Pending returns the keys typed so far of an unfinished key sequence.
+func (ks *KeySequence) Pending() string {
+	return strings.Join(ks.pending, " ")
+}
+
+// Count returns the count typed so far, or zero if there is none.
+func (ks *KeySequence) Count() int {
+	return ks.count
+}
+
+// Reset discards the keys and the count typed so far.
+func (ks *KeySequence) Reset() {
+	ks.count = 0
+	ks.resetPending()
+}
+
+func (ks *KeySequence) resetPending() {
+	hadPending := len(ks.pending) > 0
+	ks.pending = nil
+
+	if ks.timer != nil {
+		ks.timer.Stop()
+		ks.timer = nil
+	}
+
+	if hadPending {
+		ks.pendingChanged()
+	}
+}
+
+// Hold stops the timeout of the pending keys, which then wait for the next
+// key however long it takes.
+func (ks *KeySequence) Hold() {
+	if ks.timer != nil {
+		ks.timer.Stop()
+		ks.timer = nil
+	}
+}
+
+func (ks *KeySequence) pendingChanged() {
+	if ks.OnPendingChange != nil {
+		ks.OnPendingChange(ks.Pending())
+	}
+}
+
+// wait adds chord to the pending keys and waits for the next one. If no key
+// is typed before the timeout, onTimeout is called.
+func (ks *KeySequence) wait(chord string, onTimeout func(count int)) {
+	if ks.timer != nil {
+		ks.timer.Stop()
+	}
+
+	ks.pending = append(ks.pending, chord)
+
+	var timer *time.Timer
+	timer = time.AfterFunc(ks.Timeout, func() {
+		fyne.Do(func() {
+			// The sequence continued or was reset after this timer expired
+			if ks.timer != timer {
+				return
+			}
+
+			count := ks.count
+			ks.Reset()
+			onTimeout(count)
+		})
+	})
+	ks.timer = timer
+
+	ks.pendingChanged()
+}
+
 type KeyBindings struct {
-	keyBindings  map[string]bool
-	onKeyBinding func(string)
+	// sequences maps normalized key sequences to the key bindings as they are
+	// defined in the settings.
+	sequences    map[string]string
+	keySequence  *KeySequence
+	onKeyBinding func(kb string, count int)
+
+	countsEnabled bool
 }
 
-func NewKeyBindings(kbs []string, onKeyBinding func(string)) *KeyBindings {
-	keyBindings := map[string]bool{}
+func NewKeyBindings(kbs []string, leader string, keySequence *KeySequence, onKeyBinding func(string, int)) *KeyBindings {
+	sequences := map[string]string{}
 	for _, k := range kbs {
-		keyBindings[k] = true
+		sequences[normalizeKeySequence(k, leader)] = k
 	}
 
 	return &KeyBindings{
-		keyBindings:  keyBindings,
+		sequences:    sequences,
+		keySequence:  keySequence,
 		onKeyBinding: onKeyBinding,
 	}
 }
 
+// WithCounts makes digits typed before a key binding be handled as the
+// number of times to repeat it instead of as keys.
+func (kbs *KeyBindings) WithCounts() *KeyBindings {
+	kbs.countsEnabled = true
+	return kbs
+}
+
+// OnTypedKey handles keys that do not produce a character, like escape or
+// return. Keys producing characters are handled by OnTypedRune.
 func (kbs *KeyBindings) OnTypedKey(ev *fyne.KeyEvent) bool {
 	if kbs == nil {
 		return false
 	}
 
-	kb := strings.ToLower(string(ev.Name))
+	if ev.Name == fyne.KeySpace || utf8.RuneCountInString(string(ev.Name)) == 1 {
+		return false
+	}
 
-	if _, ok := kbs.keyBindings[kb]; ok {
-		kbs.onKeyBinding(kb)
-		return true
+	return kbs.OnChord(strings.ToLower(string(ev.Name)))
+}
+
+// OnTypedRune handles the character produced by a key, taking into account
+// the keyboard layout and the shift key, so "?" or "G" can be key bindings.
+func (kbs *KeyBindings) OnTypedRune(r rune) bool {
+	if kbs == nil {
+		return false
 	}
 
-	return false
+	if r == ' ' {
+		return kbs.OnChord(strings.ToLower(string(fyne.KeySpace)))
+	}
+
+	return kbs.OnChord(string(r))
 }
 
 func (kbs *KeyBindings) OnTypedShortcut(sc fyne.Shortcut) bool {
@@ -61,11 +195,204 @@ func (kbs *KeyBindings) OnTypedShortcut(sc fyne.Shortcut) bool {
 
 		kb += strings.ToLower(string(sc.KeyName))
 
-		if _, ok := kbs.keyBindings[kb]; ok {
-			kbs.onKeyBinding(kb)
+		return kbs.OnChord(kb)
+	}
+
+	return false
+}
+
+// OnChord handles a key, optionally combined with modifiers, as in
+// "ctrl shift n". It returns true if the key is part of a key binding.
+//
+// When the keys typed so far are both a key binding and the beginning of a
+// longer one, the longer one is waited for until the KeySequence timeout
+// expires.
+func (kbs *KeyBindings) OnChord(chord string) bool {
+	if kbs == nil {
+		return false
+	}
+
+	ks := kbs.keySequence
+
+	// A zero is only part of a count if it is not its first digit, so it can
+	// still be used as a key binding
+	if kbs.countsEnabled && len(ks.pending) == 0 && len(chord) == 1 &&
+		chord[0] >= '0' && chord[0] <= '9' && (chord != "0" || ks.count > 0) {
+
+		ks.count = ks.count*10 + int(chord[0]-'0')
+		return true
+	}
+
+	candidate := strings.Join(append(slices.Clone(ks.pending), chord), " ")
+	kb, isKeyBinding := kbs.sequences[candidate]
+
+	if kbs.isPrefix(candidate) {
+		ks.wait(chord, func(count int) {
+			if isKeyBinding {
+				kbs.onKeyBinding(kb, count)
+			}
+		})
+		return true
+	}
+
+	if isKeyBinding {
+		count := ks.count
+		ks.Reset()
+		kbs.onKeyBinding(kb, count)
+		return true
+	}
+
+	// The key does not continue the pending sequence, which is discarded by
+	// escape. Otherwise it runs if it is a key binding on its own, and the key
+	// is tried as the first key of a new one
+	if len(ks.pending) > 0 && chord == "escape" {
+		ks.Reset()
+		return true
+	}
+
+	if len(ks.pending) > 0 {
+		if pendingKb, ok := kbs.sequences[ks.Pending()]; ok {
+			count := ks.count
+			ks.Reset()
+			kbs.onKeyBinding(pendingKb, count)
+		} else {
+			ks.resetPending()
+		}
+
+		return kbs.OnChord(chord)
+	}
+
+	ks.Reset()
+
+	return false
+}
+
+// isPrefix returns true if sequence is the beginning of a longer key binding.
+func (kbs *KeyBindings) isPrefix(sequence string) bool {
+	for s := range kbs.sequences {
+		if strings.HasPrefix(s, sequence+" ") {
 			return true
 		}
 	}
 
 	return false
 }
+
+// normalizeKeySequence writes a key binding definition the way its keys are
+// received: <leader> is replaced by the leader key, modifiers are sorted and
+// lowercased, and so are key names. Single characters keep their case unless
+// they are combined with modifiers other than shift, and a shifted character
+// is written as the character it produces, so "shift g" becomes "G".
+func normalizeKeySequence(kb, leader string) string {
+	keys := []string{}
+	for _, k := range strings.Fields(kb) {
+		if strings.EqualFold(k, "<leader>") {
+			keys = append(keys, strings.Fields(leader)...)
+			continue
+		}
+
+		keys = append(keys, k)
+	}
+
+	chords := splitChords(strings.Join(keys, " "))
+	for i, chord := range chords {
+		chords[i] = normalizeChord(chord)
+	}
+
+	return strings.Join(chords, " ")
+}
+
+func normalizeChord(chord string) string {
+	keys := strings.Fields(chord)
+	key := keys[len(keys)-1]
+
+	modifiers := map[string]bool{}
+	for _, k := range keys[:len(keys)-1] {
+		modifiers[strings.ToLower(k)] = true
+	}
+
+	if utf8.RuneCountInString(key) > 1 || modifiers["ctrl"] || modifiers["alt"] || modifiers["super"] {
+		key = strings.ToLower(key)
+	} else if modifiers["shift"] {
+		key = strings.ToUpper(key)
+		delete(modifiers, "shift")
+	}
+
+	normalized := []string{}
+	for _, m := range modifierKeys {
+		if modifiers[m] {
+			normalized = append(normalized, m)
+		}
+	}
+
+	return strings.Join(append(normalized, key), " ")
+}
+
+// splitChords splits a key sequence into its chords, each one a key
+// optionally preceded by modifiers, as in "ctrl w".
+func splitChords(sequence string) []string {
+	chords := []string{}
+	chord := []string{}
+	for _, k := range strings.Fields(sequence) {
+		chord = append(chord, k)
+		if !isModifierKey(k) {
+			chords = append(chords, strings.Join(chord, " "))
+			chord = []string{}
+		}
+	}
+
+	return chords
+}
+
+// modifierKeys lists the modifiers in the order they are written in chords.
+var modifierKeys = []string{"ctrl", "shift", "alt", "super"}
+
+func isModifierKey(k string) bool {
+	return slices.Contains(modifierKeys, strings.ToLower(k))
+}
+
+// chordShortcut returns the shortcut a chord with modifiers is received as.
+// It returns false for keys without modifiers, which are not received as
+// shortcuts.
+func chordShortcut(chord string) (*desktop.CustomShortcut, bool) {
+	keys := strings.Fields(chord)
+	if len(keys) < 2 {
+		return nil, false
+	}
+
+	var mod fyne.KeyModifier
+	for _, k := range keys[:len(keys)-1] {
+		switch k {
+		case "ctrl":
+			mod |= fyne.KeyModifierControl
+		case "shift":
+			mod |= fyne.KeyModifierShift
+		case "alt":
+			mod |= fyne.KeyModifierAlt
+		case "super":
+			mod |= fyne.KeyModifierSuper
+		}
+	}
+
+	return &desktop.CustomShortcut{KeyName: fyneKeyName(keys[len(keys)-1]), Modifier: mod}, true
+}
+
+var namedKeys = []fyne.KeyName{
+	fyne.KeyEscape, fyne.KeyReturn, fyne.KeyTab, fyne.KeyBackspace, fyne.KeyInsert,
+	fyne.KeyDelete, fyne.KeyRight, fyne.KeyLeft, fyne.KeyDown, fyne.KeyUp,
+	fyne.KeyPageUp, fyne.KeyPageDown, fyne.KeyHome, fyne.KeyEnd, fyne.KeySpace,
+	fyne.KeyEnter, fyne.KeyF1, fyne.KeyF2, fyne.KeyF3, fyne.KeyF4, fyne.KeyF5,
+	fyne.KeyF6, fyne.KeyF7, fyne.KeyF8, fyne.KeyF9, fyne.KeyF10, fyne.KeyF11,
+	fyne.KeyF12,
+}
+
+// fyneKeyName returns the name fyne uses for the key k of a key binding.
+func fyneKeyName(k string) fyne.KeyName {
+	for _, name := range namedKeys {
+		if strings.EqualFold(string(name), k) {
+			return name
+		}
+	}
+
+	return fyne.KeyName(strings.ToUpper(k))
+}
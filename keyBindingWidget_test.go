@@ -0,0 +1,148 @@
+package efinui
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestNormalizeKeySequence(t *testing.T) {
+	tests := []struct {
+		kb     string
+		leader string
+		want   string
+	}{
+		{kb: "j", leader: "space", want: "j"},
+		{kb: "G", leader: "space", want: "G"},
+		{kb: "g g", leader: "space", want: "g g"},
+		{kb: "shift g", leader: "space", want: "G"},
+		{kb: "Escape", leader: "space", want: "escape"},
+		{kb: "ctrl N", leader: "space", want: "ctrl n"},
+		{kb: "Shift Ctrl W", leader: "space", want: "ctrl shift w"},
+		{kb: "alt ctrl 1", leader: "space", want: "ctrl alt 1"},
+		{kb: "ctrl w  j", leader: "space", want: "ctrl w j"},
+		{kb: "<leader> e p", leader: "space", want: "space e p"},
+		{kb: "<Leader> t", leader: ",", want: ", t"},
+		{kb: "<leader> t", leader: "ctrl w", want: "ctrl w t"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.kb, func(t *testing.T) {
+			if got := normalizeKeySequence(tt.kb, tt.leader); got != tt.want {
+				t.Errorf("normalizeKeySequence(%q, %q) = %q, want %q", tt.kb, tt.leader, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKeyBindingsOnChord(t *testing.T) {
+	tests := []struct {
+		name   string
+		kbs    []string
+		counts bool
+		chords []string
+
+		// want holds the key bindings run, as "kb:count"
+		want        []string
+		wantPending string
+	}{
+		{
+			name:   "single key",
+			kbs:    []string{"j"},
+			chords: []string{"j"},
+			want:   []string{"j:0"},
+		},
+		{
+			name:   "unbound key",
+			kbs:    []string{"j"},
+			chords: []string{"x", "j"},
+			want:   []string{"j:0"},
+		},
+		{
+			name:   "count",
+			kbs:    []string{"j"},
+			counts: true,
+			chords: []string{"1", "0", "j"},
+			want:   []string{"j:10"},
+		},
+		{
+			name:   "digits without counts",
+			kbs:    []string{"1"},
+			chords: []string{"1"},
+			want:   []string{"1:0"},
+		},
+		{
+			name:   "zero bound without a count",
+			kbs:    []string{"0", "j"},
+			counts: true,
+			chords: []string{"0", "2", "0", "j"},
+			want:   []string{"0:0", "j:20"},
+		},
+		{
+			name:   "sequence",
+			kbs:    []string{"g g"},
+			chords: []string{"g", "g"},
+			want:   []string{"g g:0"},
+		},
+		{
+			name:        "pending sequence",
+			kbs:         []string{"g g"},
+			chords:      []string{"g"},
+			wantPending: "g",
+		},
+		{
+			name:   "sequence with modifiers",
+			kbs:    []string{"ctrl w j"},
+			chords: []string{"ctrl w", "j"},
+			want:   []string{"ctrl w j:0"},
+		},
+		{
+			name:   "pending key binding runs before a key not continuing it",
+			kbs:    []string{"g", "g g", "j"},
+			counts: true,
+			chords: []string{"3", "g", "j"},
+			want:   []string{"g:3", "j:0"},
+		},
+		{
+			name:   "key not continuing a sequence starts a new one",
+			kbs:    []string{"g g", "d d"},
+			chords: []string{"g", "d", "d"},
+			want:   []string{"d d:0"},
+		},
+		{
+			name:   "escape discards a pending sequence",
+			kbs:    []string{"g", "g g", "escape"},
+			chords: []string{"g", "escape", "escape"},
+			want:   []string{"escape:0"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ks := NewKeySequence()
+			ks.Timeout = time.Hour
+			defer ks.Reset()
+
+			got := []string{}
+			kbs := NewKeyBindings(tt.kbs, DefaultLeaderKey, ks, func(kb string, count int) {
+				got = append(got, fmt.Sprintf("%s:%d", kb, count))
+			})
+			if tt.counts {
+				kbs.WithCounts()
+			}
+
+			for _, chord := range tt.chords {
+				kbs.OnChord(chord)
+			}
+
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("key bindings run = %q, want %q", got, tt.want)
+			}
+
+			if pending := ks.Pending(); pending != tt.wantPending {
+				t.Errorf("pending = %q, want %q", pending, tt.wantPending)
+			}
+		})
+	}
+}
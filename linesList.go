@@ -20,8 +20,17 @@ type LinesList struct {
 	originalLines []string
 	viewLines     []string
 
+	// viewLinesOrigin holds, for every view line, the index of the original
+	// line it was wrapped from.
+	viewLinesOrigin []int
+
 	selectedLine int
 
+	// visualAnchor is the view line where the visual selection started, the
+	// selected lines go from it to selectedLine.
+	visualAnchor int
+	visualActive bool
+
 	search              string
 	searchCaseSensitive bool
 	searchResults       []int
@@ -43,10 +52,27 @@ func NewLinesList(text string) *LinesList {
 	list = widget.NewList(
 		func() int { return len(ll.viewLines) },
 		func() fyne.CanvasObject {
-			return canvas.NewText("", list.Theme().Color(theme.ColorNameForeground, theme.VariantDark))
+			highlight := canvas.NewRectangle(list.Theme().Color(theme.ColorNameSelection, theme.VariantDark))
+			highlight.Hide()
+
+			return container.NewStack(
+				highlight,
+				canvas.NewText("", list.Theme().Color(theme.ColorNameForeground, theme.VariantDark)),
+			)
 		},
 		func(id widget.ListItemID, obj fyne.CanvasObject) {
-			txt := obj.(*canvas.Text)
+			c := obj.(*fyne.Container)
+
+			highlight := c.Objects[0].(*canvas.Rectangle)
+			if ll.isLineSelected(id) {
+				highlight.FillColor = list.Theme().Color(theme.ColorNameSelection, theme.VariantDark)
+				highlight.Show()
+			} else {
+				highlight.Hide()
+			}
+			highlight.Refresh()
+
+			txt := c.Objects[1].(*canvas.Text)
 			txt.Text = ll.viewLines[id]
 			txt.Color = list.Theme().Color(theme.ColorNameForeground, theme.VariantDark)
 			txt.Refresh()
@@ -94,7 +120,7 @@ func (ll *LinesList) refreshWrappedContent() {
 		maxChars = 90
 	}
 
-	ll.viewLines = wrapLines(ll.originalLines, maxChars)
+	ll.viewLines, ll.viewLinesOrigin = wrapLines(ll.originalLines, maxChars)
 
 	if ll.search != "" {
 		ll.Search(ll.search, ll.searchCaseSensitive)
@@ -103,11 +129,15 @@ func (ll *LinesList) refreshWrappedContent() {
 	ll.list.Refresh()
 }
 
-func wrapLines(original []string, maxChars int) []string {
+// wrapLines splits the lines longer than maxChars. It also returns the index
+// of the original line every resulting line comes from.
+func wrapLines(original []string, maxChars int) ([]string, []int) {
 	var result []string
-	for _, line := range original {
+	var origin []int
+	for n, line := range original {
 		if len(line) <= maxChars {
 			result = append(result, line)
+			origin = append(origin, n)
 			continue
 		}
 		// Hard split (you can improve with word-aware splitting if wanted)
@@ -117,9 +147,10 @@ func wrapLines(original []string, maxChars int) []string {
 				end = len(line)
 			}
 			result = append(result, line[i:end])
+			origin = append(origin, n)
 		}
 	}
-	return result
+	return result, origin
 }
 
 func (ll *LinesList) Resize(s fyne.Size) {
@@ -180,23 +211,134 @@ func (ll *LinesList) SearchNext() {
 }
 
 func (ll *LinesList) MoveUp() {
+	ll.moveLines(-1)
+}
+
+func (ll *LinesList) MoveDown() {
+	ll.moveLines(1)
+}
+
+func (ll *LinesList) MoveLeft() {}
+
+func (ll *LinesList) MoveRight() {}
+
+func (ll *LinesList) MovePageUp() {
+	ll.moveLines(-ll.pageSize())
+}
+
+func (ll *LinesList) MovePageDown() {
+	ll.moveLines(ll.pageSize())
+}
+
+func (ll *LinesList) MoveHalfPageUp() {
+	ll.moveLines(-max(1, ll.pageSize()/2))
+}
+
+func (ll *LinesList) MoveHalfPageDown() {
+	ll.moveLines(max(1, ll.pageSize()/2))
+}
+
+func (ll *LinesList) MoveTop() {
+	ll.moveLines(-len(ll.viewLines))
+}
+
+func (ll *LinesList) MoveBottom() {
+	ll.moveLines(len(ll.viewLines))
+}
+
+func (ll *LinesList) MoveFirstColumn() {}
+
+func (ll *LinesList) MoveLastColumn() {}
+
+// moveLines moves the selection delta lines down, or up if delta is
+// negative, without going past the first or last line.
+func (ll *LinesList) moveLines(delta int) {
 	if len(ll.viewLines) == 0 {
 		return
 	}
-	ll.selectedLine = max(0, ll.selectedLine-1)
+	ll.selectedLine = min(max(0, ll.selectedLine+delta), len(ll.viewLines)-1)
 	ll.list.ScrollTo(ll.selectedLine)
 	ll.list.Select(ll.selectedLine)
 	ll.list.Refresh()
 }
 
-func (ll *LinesList) MoveDown() {
+// pageSize returns the number of lines that fit in the list.
+func (ll *LinesList) pageSize() int {
+	lineHeight := ll.list.CreateItem().MinSize().Height + ll.Theme().Size(theme.SizeNameSeparatorThickness)
+
+	return max(1, int(ll.list.Size().Height/lineHeight))
+}
+
+// VisualStart starts selecting lines from the current one. Moving up or down
+// extends the selection.
+func (ll *LinesList) VisualStart() {
+	ll.visualAnchor = ll.selectedLine
+	ll.visualActive = true
+
+	ll.list.Refresh()
+}
+
+func (ll *LinesList) VisualStop() {
+	ll.visualActive = false
+
+	ll.list.Refresh()
+}
+
+func (ll *LinesList) isLineSelected(line int) bool {
+	return ll.visualActive &&
+		line >= min(ll.visualAnchor, ll.selectedLine) &&
+		line <= max(ll.visualAnchor, ll.selectedLine)
+}
+
+// SelectedText returns the lines selected in visual mode, or the current line
+// if there is no visual selection. Lines that were wrapped to fit the list
+// are joined back.
+func (ll *LinesList) SelectedText() string {
 	if len(ll.viewLines) == 0 {
-		return
+		return ""
 	}
-	ll.selectedLine = min(len(ll.viewLines)-1, ll.selectedLine+1)
-	ll.list.ScrollTo(ll.selectedLine)
-	ll.list.Select(ll.selectedLine)
-	ll.list.Refresh()
+
+	first, last := ll.selectedLine, ll.selectedLine
+	if ll.visualActive {
+		first, last = min(ll.visualAnchor, ll.selectedLine), max(ll.visualAnchor, ll.selectedLine)
+	}
+
+	return ll.linesText(first, last)
+}
+
+// RowsText returns count lines from the current one, without selecting them.
+func (ll *LinesList) RowsText(count int) string {
+	if len(ll.viewLines) == 0 {
+		return ""
+	}
+
+	return ll.linesText(ll.selectedLine, ll.selectedLine+max(1, count)-1)
+}
+
+// linesText returns the view lines from first to last, joining back the
+// lines that were wrapped.
+func (ll *LinesList) linesText(first, last int) string {
+	// The lines may have been wrapped again since they were selected
+	first = min(first, len(ll.viewLines)-1)
+	last = min(last, len(ll.viewLines)-1)
+
+	text := &strings.Builder{}
+	for i := first; i <= last; i++ {
+		if i > first && ll.viewLinesOrigin[i] != ll.viewLinesOrigin[i-1] {
+			text.WriteString("\n")
+		}
+		text.WriteString(ll.viewLines[i])
+	}
+
+	return text.String()
+}
+
+func (ll *LinesList) RowCount() int {
+	return len(ll.viewLines)
+}
+
+func (ll *LinesList) CurrentRow() int {
+	return ll.selectedLine
 }
 
 func (ll *LinesList) updateSearchResultsBox() {
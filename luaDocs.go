@@ -0,0 +1,105 @@
+package efinui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+// LuaFunctionDoc documents a function available from Lua, or a user command.
+type LuaFunctionDoc struct {
+	Name        string
+	Signature   string
+	Description string
+}
+
+// luaRegister sets fn as a Lua global named as in signature, like "query" for
+// "query(sql)", and keeps its documentation for help().
+func (a *App) luaRegister(fn *lua.LFunction, signature, description string) {
+	name, _, _ := strings.Cut(signature, "(")
+
+	a.l.SetGlobal(name, fn)
+	a.luaDocAdd(LuaFunctionDoc{
+		Name:        name,
+		Signature:   signature,
+		Description: description,
+	})
+
+	a.luaFunctionNames[fn] = name
+}
+
+// luaFunctionDescription returns the description of a function registered
+// with luaRegister.
+func (a *App) luaFunctionDescription(fn *lua.LFunction) (string, bool) {
+	name, ok := a.luaFunctionNames[fn]
+	if !ok {
+		return "", false
+	}
+
+	return a.luaDocs[name].Description, true
+}
+
+func (a *App) luaDocAdd(doc LuaFunctionDoc) {
+	a.luaDocs[doc.Name] = doc
+}
+
+// userCommandSignature returns how the arguments of uc are typed, as in
+// "Host arg" or "Filter [args...]".
+func userCommandSignature(uc *UserCommand) string {
+	args := map[string]string{
+		"0": "",
+		"1": " arg",
+		"?": " [arg]",
+		"*": " [args...]",
+		"+": " args...",
+	}
+
+	return ":" + uc.Name + args[uc.NArgs]
+}
+
+// luaDocsText returns the signature and description of every documented
+// function.
+func (a *App) luaDocsText() string {
+	names := []string{}
+	for name := range a.luaDocs {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	lines := []string{`Lua API, type help("name") for details`}
+	for _, name := range names {
+		doc := a.luaDocs[name]
+		lines = append(lines, "", doc.Signature, "    "+doc.Description)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// luaDocText returns the documentation of the function called name.
+func (a *App) luaDocText(name string) (string, bool) {
+	doc, ok := a.luaDocs[name]
+	if !ok {
+		return "", false
+	}
+
+	return doc.Signature + "\n\n" + doc.Description, true
+}
+
+// Help opens a pane with the documentation of the function called name, or
+// of every function if name is empty.
+func (a *App) Help(name string) error {
+	text := a.luaDocsText()
+	if name != "" {
+		var ok bool
+		text, ok = a.luaDocText(name)
+		if !ok {
+			return fmt.Errorf("no documentation found for %q", name)
+		}
+	}
+
+	a.tabs[a.currentTabIndex].PaneCreate(NewLinesList(text))
+
+	return nil
+}
@@ -0,0 +1,198 @@
+package efinui
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+// MessageLogMaxSize is the number of messages kept in memory, the oldest
+// ones are dropped first.
+const MessageLogMaxSize = 1000
+
+type MessageLevel int
+
+const (
+	MessageLevelInfo MessageLevel = iota
+	MessageLevelSuccess
+	MessageLevelWarning
+	MessageLevelError
+)
+
+func (ml MessageLevel) String() string {
+	switch ml {
+	case MessageLevelInfo:
+		return "info"
+	case MessageLevelSuccess:
+		return "success"
+	case MessageLevelWarning:
+		return "warning"
+	case MessageLevelError:
+		return "error"
+	}
+
+	return "unknown"
+}
+
+// MessageLevelFromString returns the level named name, as in "warning".
+func MessageLevelFromString(name string) (MessageLevel, bool) {
+	for _, level := range []MessageLevel{MessageLevelInfo, MessageLevelSuccess, MessageLevelWarning, MessageLevelError} {
+		if level.String() == name {
+			return level, true
+		}
+	}
+
+	return MessageLevelInfo, false
+}
+
+type LogMessage struct {
+	Time  time.Time
+	Level MessageLevel
+	Text  string
+}
+
+func (m LogMessage) String() string {
+	return fmt.Sprintf("%s %-7s %s", m.Time.Format(time.DateTime), strings.ToUpper(m.Level.String()), m.Text)
+}
+
+// MessageLog records the toasts, errors and printed text of the session, and
+// writes them to a file once one is set.
+type MessageLog struct {
+	lock sync.Mutex
+
+	messages []LogMessage
+	file     *os.File
+}
+
+func NewMessageLog() *MessageLog {
+	return &MessageLog{}
+}
+
+func (ml *MessageLog) Add(level MessageLevel, text string) {
+	ml.lock.Lock()
+	defer ml.lock.Unlock()
+
+	m := LogMessage{
+		Time:  time.Now(),
+		Level: level,
+		Text:  text,
+	}
+
+	ml.messages = append(ml.messages, m)
+	if len(ml.messages) > MessageLogMaxSize {
+		ml.messages = slices.Clone(ml.messages[len(ml.messages)-MessageLogMaxSize:])
+	}
+
+	if ml.file != nil {
+		if _, err := fmt.Fprintln(ml.file, m); err != nil {
+			log.Printf("could not write to message log file: %v", err)
+		}
+	}
+}
+
+// Messages returns the messages recorded, from the oldest to the newest.
+func (ml *MessageLog) Messages() []LogMessage {
+	ml.lock.Lock()
+	defer ml.lock.Unlock()
+
+	return slices.Clone(ml.messages)
+}
+
+// SetFile appends the messages recorded so far, and the next ones, to the
+// file at path.
+func (ml *MessageLog) SetFile(path string) error {
+	ml.lock.Lock()
+	defer ml.lock.Unlock()
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range ml.messages {
+		if _, err := fmt.Fprintln(f, m); err != nil {
+			f.Close()
+			return err
+		}
+	}
+
+	if ml.file != nil {
+		ml.file.Close()
+	}
+	ml.file = f
+
+	return nil
+}
+
+func (ml *MessageLog) Close() error {
+	ml.lock.Lock()
+	defer ml.lock.Unlock()
+
+	if ml.file == nil {
+		return nil
+	}
+
+	err := ml.file.Close()
+	ml.file = nil
+
+	return err
+}
+
+// logErrorf prints an error to the standard error and records it in the
+// message log.
+func (a *App) logErrorf(format string, args ...any) {
+	text := fmt.Sprintf(format, args...)
+
+	log.Print(text)
+	a.messageLog.Add(MessageLevelError, text)
+}
+
+// messageLogFileSet writes the message log to settings.message_log_file, if
+// it is set.
+func (a *App) messageLogFileSet() {
+	settingsTable, ok := a.l.GetGlobal("settings").(*lua.LTable)
+	if !ok {
+		return
+	}
+
+	path, ok := settingsTable.RawGet(lua.LString("message_log_file")).(lua.LString)
+	if !ok || path == "" {
+		return
+	}
+
+	if err := a.messageLog.SetFile(string(path)); err != nil {
+		a.ToastError(fmt.Sprintf("ERROR: could not open message log file: %v", err))
+	}
+}
+
+// Messages opens a pane with the messages recorded so far in the message log.
+// The pane is a snapshot, it does not show the messages recorded later.
+func (a *App) Messages() {
+	lines := []string{}
+	for _, m := range a.messageLog.Messages() {
+		lines = append(lines, m.String())
+	}
+
+	if len(lines) == 0 {
+		lines = append(lines, "No messages")
+	}
+
+	a.tabs[a.currentTabIndex].PaneCreate(NewLinesList(strings.Join(lines, "\n")))
+}
+
+// luaPrintText returns the arguments of a call to print as print writes
+// them, separated by tabs.
+func luaPrintText(ls *lua.LState) string {
+	args := []string{}
+	for i := 1; i <= ls.GetTop(); i++ {
+		args = append(args, ls.ToStringMeta(ls.Get(i)).String())
+	}
+
+	return strings.Join(args, "\t")
+}
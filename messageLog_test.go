@@ -0,0 +1,28 @@
+package efinui
+
+import "testing"
+
+func TestMessageLevelFromString(t *testing.T) {
+	tests := []struct {
+		name   string
+		want   MessageLevel
+		wantOk bool
+	}{
+		{name: "info", want: MessageLevelInfo, wantOk: true},
+		{name: "success", want: MessageLevelSuccess, wantOk: true},
+		{name: "warning", want: MessageLevelWarning, wantOk: true},
+		{name: "error", want: MessageLevelError, wantOk: true},
+		{name: "Error", want: MessageLevelInfo, wantOk: false},
+		{name: "unknown", want: MessageLevelInfo, wantOk: false},
+		{name: "", want: MessageLevelInfo, wantOk: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := MessageLevelFromString(tt.name)
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("MessageLevelFromString(%q) = %v, %v, want %v, %v", tt.name, got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
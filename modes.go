@@ -1,25 +1,90 @@
 package efinui
 
-type Mode int
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Mode is a built-in mode or a mode defined with App.ModeDefine. Modes are
+// compared by identity, not by name.
+type Mode struct {
+	def *modeDefinition
+}
+
+type modeDefinition struct {
+	name  string
+	entry bool
+}
+
+func newMode(name string, entry bool) Mode {
+	return Mode{def: &modeDefinition{name: name, entry: entry}}
+}
 
 func (m Mode) String() string {
-	switch m {
-	case ModeNormal:
-		return "normal"
-	case ModeCommand:
-		return "command"
-	case ModeSearch:
-		return "search"
-	case ModeHelp:
-		return "help"
+	if m.def == nil {
+		return "unknown"
 	}
 
-	return "unknown"
+	return m.def.name
 }
 
-const (
-	ModeNormal Mode = iota
-	ModeCommand
-	ModeSearch
-	ModeHelp
+// Entry returns true if text is typed in the command entry in this mode. In
+// help mode, it filters the key bindings shown.
+func (m Mode) Entry() bool {
+	return m.def != nil && m.def.entry
+}
+
+var (
+	ModeNormal  = newMode("normal", false)
+	ModeCommand = newMode("command", true)
+	ModeSearch  = newMode("search", true)
+	ModeHelp    = newMode("help", true)
+	ModeVisual  = newMode("visual", false)
+	ModeSQL     = newMode("sql", true)
+	ModeInsert  = newMode("insert", false)
 )
+
+var builtinModes = []Mode{ModeNormal, ModeCommand, ModeSearch, ModeHelp, ModeVisual, ModeSQL, ModeInsert}
+
+// ModeDefine adds a mode named name, or updates it if it was already defined,
+// and returns it. entry tells whether text is typed in the command entry in
+// the mode. The names of the built-in modes and of the widgets, whose key
+// bindings share settings.key_bindings with the modes, can not be used.
+func (a *App) ModeDefine(name string, entry bool) (Mode, error) {
+	if slices.ContainsFunc(builtinModes, func(m Mode) bool { return m.String() == name }) {
+		return ModeNormal, fmt.Errorf("%q is a built-in mode", name)
+	}
+
+	if slices.Contains(keyBinderWidgetNames, name) {
+		return ModeNormal, fmt.Errorf("%q is the name of a widget", name)
+	}
+
+	// The key bindings of a widget for a mode are kept as "widget.mode"
+	if name == "" || strings.Contains(name, ".") {
+		return ModeNormal, fmt.Errorf("invalid mode name %q", name)
+	}
+
+	if m, ok := a.customModes[name]; ok {
+		m.def.entry = entry
+		return m, nil
+	}
+
+	m := newMode(name, entry)
+	a.customModes[name] = m
+
+	return m, nil
+}
+
+// ModeFromString returns the built-in or custom mode named name.
+func (a *App) ModeFromString(name string) (Mode, bool) {
+	for _, m := range builtinModes {
+		if m.String() == name {
+			return m, true
+		}
+	}
+
+	m, ok := a.customModes[name]
+
+	return m, ok
+}
@@ -2,6 +2,7 @@ package efinui
 
 import (
 	"image/color"
+	"slices"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/canvas"
@@ -15,6 +16,9 @@ type MultiSplit struct {
 
 	OnFocusMove func(fyne.CanvasObject)
 
+	name    string
+	renamed bool
+
 	objectsGrid [][]fyne.CanvasObject
 
 	layout Layout
@@ -22,6 +26,8 @@ type MultiSplit struct {
 	focusedIndex1 int
 	focusedIndex2 int
 
+	zoomed bool
+
 	search              string
 	searchCaseSensitive bool
 
@@ -47,7 +53,29 @@ func (ms *MultiSplit) CreateRenderer() fyne.WidgetRenderer {
 	return widget.NewSimpleRenderer(ms.container)
 }
 
+func (ms *MultiSplit) Name() string {
+	return ms.name
+}
+
+// Rename sets a name chosen by the user, which is not replaced by later calls
+// to SetDefaultName.
+func (ms *MultiSplit) Rename(name string) {
+	ms.name = name
+	ms.renamed = name != ""
+}
+
+// SetDefaultName sets a name derived from the tab contents, unless the user
+// already renamed the tab.
+func (ms *MultiSplit) SetDefaultName(name string) {
+	if ms.renamed {
+		return
+	}
+
+	ms.name = name
+}
+
 func (ms *MultiSplit) PaneLineAdd(o fyne.CanvasObject) {
+	ms.zoomed = false
 	ms.objectsGrid = append(ms.objectsGrid, []fyne.CanvasObject{o})
 	ms.focusedIndex1 = len(ms.objectsGrid) - 1
 	ms.focusedIndex2 = 0
@@ -63,6 +91,8 @@ func (ms *MultiSplit) PaneCreate(o fyne.CanvasObject) {
 		}
 	}
 
+	ms.zoomed = false
+
 	if len(ms.objectsGrid) == 0 {
 		ms.objectsGrid = [][]fyne.CanvasObject{[]fyne.CanvasObject{}}
 		ms.focusedIndex1 = 0
@@ -77,6 +107,16 @@ func (ms *MultiSplit) PaneCreate(o fyne.CanvasObject) {
 }
 
 func (ms *MultiSplit) refreshContainer() {
+	flexGrid := ms.container.Layout.(*FlexGrid)
+
+	if focused := ms.focusedPane(); ms.zoomed && focused != nil {
+		flexGrid.ItemsPerGroup = []int{1}
+
+		ms.container.Objects = []fyne.CanvasObject{ms.widgetWithFocusStyle(focused)}
+		ms.container.Refresh()
+		return
+	}
+
 	objects := []fyne.CanvasObject{}
 	for i, g := range ms.objectsGrid {
 		for j, o := range g {
@@ -92,7 +132,6 @@ func (ms *MultiSplit) refreshContainer() {
 		groupsLenghts[i] = len(g)
 	}
 
-	flexGrid := ms.container.Layout.(*FlexGrid)
 	flexGrid.ItemsPerGroup = groupsLenghts
 
 	ms.container.Objects = objects
@@ -100,10 +139,35 @@ func (ms *MultiSplit) refreshContainer() {
 }
 
 func (ms *MultiSplit) PaneDelete() {
-	if len(ms.objectsGrid) <= ms.focusedIndex1 || len(ms.objectsGrid[ms.focusedIndex1]) <= ms.focusedIndex2 {
-		return
+	ms.PaneRemove()
+}
+
+// PanePosition identifies where a pane was placed in the grid.
+type PanePosition struct {
+	Line  int
+	Index int
+
+	// OnlyPaneInLine is true if the line was removed together with the pane.
+	OnlyPaneInLine bool
+}
+
+// PaneRemove removes the focused pane from the grid and returns it along with
+// its position, so it can be placed somewhere else or restored later. It
+// returns nil if there is no focused pane.
+func (ms *MultiSplit) PaneRemove() (fyne.CanvasObject, PanePosition) {
+	removed := ms.focusedPane()
+	if removed == nil {
+		return nil, PanePosition{}
 	}
 
+	position := PanePosition{
+		Line:           ms.focusedIndex1,
+		Index:          ms.focusedIndex2,
+		OnlyPaneInLine: len(ms.objectsGrid[ms.focusedIndex1]) == 1,
+	}
+
+	ms.zoomed = false
+
 	group := []fyne.CanvasObject{}
 	for i, o := range ms.objectsGrid[ms.focusedIndex1] {
 		if i == ms.focusedIndex2 {
@@ -138,6 +202,35 @@ func (ms *MultiSplit) PaneDelete() {
 
 	ms.refreshContainer()
 	ms.callOnFocusMove()
+
+	return removed, position
+}
+
+// PaneRestore places o at the given position, as close as possible to where
+// it was when it was removed, and focuses it.
+func (ms *MultiSplit) PaneRestore(o fyne.CanvasObject, position PanePosition) {
+	if ms.search != "" {
+		if searchable, ok := o.(Searcher); ok {
+			searchable.Search(ms.search, ms.searchCaseSensitive)
+		}
+	}
+
+	ms.zoomed = false
+
+	line := min(max(0, position.Line), len(ms.objectsGrid))
+	if position.OnlyPaneInLine || line == len(ms.objectsGrid) {
+		ms.objectsGrid = slices.Insert(ms.objectsGrid, line, []fyne.CanvasObject{o})
+		ms.focusedIndex1 = line
+		ms.focusedIndex2 = 0
+	} else {
+		index := min(max(0, position.Index), len(ms.objectsGrid[line]))
+		ms.objectsGrid[line] = slices.Insert(ms.objectsGrid[line], index, o)
+		ms.focusedIndex1 = line
+		ms.focusedIndex2 = index
+	}
+
+	ms.refreshContainer()
+	ms.callOnFocusMove()
 }
 
 func (ms *MultiSplit) SetCurrentPane(o fyne.CanvasObject) {
@@ -160,77 +253,108 @@ func (ms *MultiSplit) SetCurrentPane(o fyne.CanvasObject) {
 }
 
 func (ms *MultiSplit) PaneFocusUp() {
+	ms.paneFocus(DirectionUp)
+}
+
+func (ms *MultiSplit) PaneFocusDown() {
+	ms.paneFocus(DirectionDown)
+}
+
+func (ms *MultiSplit) PaneFocusLeft() {
+	ms.paneFocus(DirectionLeft)
+}
+
+func (ms *MultiSplit) PaneFocusRight() {
+	ms.paneFocus(DirectionRight)
+}
+
+func (ms *MultiSplit) paneFocus(direction Direction) {
 	if len(ms.objectsGrid) == 0 {
 		return
 	}
 
-	if ms.layout == LayoutRowColumn {
-		ms.focusedIndex1 = max(ms.focusedIndex1-1, 0)
-		ms.focusedIndex2 = min(ms.focusedIndex2, len(ms.objectsGrid[ms.focusedIndex1])-1)
-	} else {
-		ms.focusedIndex2 = max(ms.focusedIndex2-1, 0)
-	}
+	ms.focusedIndex1, ms.focusedIndex2 = ms.paneIndexInDirection(direction)
 
 	ms.refreshContainer()
 	ms.callOnFocusMove()
 }
 
-func (ms *MultiSplit) callOnFocusMove() {
-	if ms.OnFocusMove != nil {
-		if len(ms.objectsGrid) == 0 || len(ms.objectsGrid[ms.focusedIndex1]) == 0 {
-			ms.OnFocusMove(nil)
-		} else {
-			ms.OnFocusMove(ms.objectsGrid[ms.focusedIndex1][ms.focusedIndex2])
-		}
+// PaneSwap exchanges the focused pane with its neighbour in the given
+// direction. The focus follows the moved pane.
+func (ms *MultiSplit) PaneSwap(direction Direction) {
+	if ms.focusedPane() == nil {
+		return
 	}
-}
 
-func (ms *MultiSplit) PaneFocusDown() {
-	if len(ms.objectsGrid) == 0 {
+	i, j := ms.paneIndexInDirection(direction)
+	if i == ms.focusedIndex1 && j == ms.focusedIndex2 {
 		return
 	}
 
-	if ms.layout == LayoutRowColumn {
-		ms.focusedIndex1 = min(ms.focusedIndex1+1, len(ms.objectsGrid)-1)
-		ms.focusedIndex2 = min(ms.focusedIndex2, len(ms.objectsGrid[ms.focusedIndex1])-1)
-	} else {
-		ms.focusedIndex2 = min(ms.focusedIndex2+1, len(ms.objectsGrid[ms.focusedIndex1])-1)
-	}
+	ms.objectsGrid[ms.focusedIndex1][ms.focusedIndex2], ms.objectsGrid[i][j] =
+		ms.objectsGrid[i][j], ms.objectsGrid[ms.focusedIndex1][ms.focusedIndex2]
+	ms.focusedIndex1, ms.focusedIndex2 = i, j
 
 	ms.refreshContainer()
 	ms.callOnFocusMove()
 }
 
-func (ms *MultiSplit) PaneFocusLeft() {
-	if len(ms.objectsGrid) == 0 {
+// PaneZoomToggle switches between showing only the focused pane using the
+// whole tab area and showing every pane. Moving the focus while zoomed shows
+// the newly focused pane, adding or removing panes restores the grid.
+func (ms *MultiSplit) PaneZoomToggle() {
+	if ms.focusedPane() == nil {
 		return
 	}
 
-	if ms.layout == LayoutRowColumn {
-		ms.focusedIndex2 = max(ms.focusedIndex2-1, 0)
-	} else {
-		ms.focusedIndex1 = max(ms.focusedIndex1-1, 0)
-		ms.focusedIndex2 = min(ms.focusedIndex2, len(ms.objectsGrid[ms.focusedIndex1])-1)
+	ms.zoomed = !ms.zoomed
+	ms.refreshContainer()
+}
+
+func (ms *MultiSplit) paneIndexInDirection(direction Direction) (int, int) {
+	i, j := ms.focusedIndex1, ms.focusedIndex2
+
+	if ms.layout == LayoutColumnRow {
+		switch direction {
+		case DirectionUp:
+			direction = DirectionLeft
+		case DirectionDown:
+			direction = DirectionRight
+		case DirectionLeft:
+			direction = DirectionUp
+		case DirectionRight:
+			direction = DirectionDown
+		}
 	}
 
-	ms.refreshContainer()
-	ms.callOnFocusMove()
+	switch direction {
+	case DirectionUp:
+		i = max(i-1, 0)
+		j = min(j, len(ms.objectsGrid[i])-1)
+	case DirectionDown:
+		i = min(i+1, len(ms.objectsGrid)-1)
+		j = min(j, len(ms.objectsGrid[i])-1)
+	case DirectionLeft:
+		j = max(j-1, 0)
+	case DirectionRight:
+		j = min(j+1, len(ms.objectsGrid[i])-1)
+	}
+
+	return i, j
 }
 
-func (ms *MultiSplit) PaneFocusRight() {
-	if len(ms.objectsGrid) == 0 {
-		return
+func (ms *MultiSplit) callOnFocusMove() {
+	if ms.OnFocusMove != nil {
+		ms.OnFocusMove(ms.focusedPane())
 	}
+}
 
-	if ms.layout == LayoutRowColumn {
-		ms.focusedIndex2 = min(ms.focusedIndex2+1, len(ms.objectsGrid[ms.focusedIndex1])-1)
-	} else {
-		ms.focusedIndex1 = min(ms.focusedIndex1+1, len(ms.objectsGrid)-1)
-		ms.focusedIndex2 = min(ms.focusedIndex2, len(ms.objectsGrid[ms.focusedIndex1])-1)
+func (ms *MultiSplit) focusedPane() fyne.CanvasObject {
+	if len(ms.objectsGrid) <= ms.focusedIndex1 || len(ms.objectsGrid[ms.focusedIndex1]) <= ms.focusedIndex2 {
+		return nil
 	}
 
-	ms.refreshContainer()
-	ms.callOnFocusMove()
+	return ms.objectsGrid[ms.focusedIndex1][ms.focusedIndex2]
 }
 
 func (ms *MultiSplit) Search(search string, caseSensitive bool) {
@@ -266,6 +390,15 @@ const (
 	LayoutRowColumn
 )
 
+type Direction int
+
+const (
+	DirectionUp Direction = iota
+	DirectionDown
+	DirectionLeft
+	DirectionRight
+)
+
 func (ms *MultiSplit) widgetWithFocusStyle(w fyne.CanvasObject) fyne.CanvasObject {
 	// Create a rectangle for the background
 	thm := ms.Theme()
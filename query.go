@@ -9,6 +9,7 @@ import (
 	"log"
 	"net/http"
 	"net/url"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -254,3 +255,64 @@ func getResponse(db *sql.DB, id string) (*Response, error) {
 
 	return &resp, nil
 }
+
+// databasePath returns the path of the file backing the main database of db,
+// or an empty string for in-memory databases.
+func databasePath(db *sql.DB) (string, error) {
+	var path string
+	err := db.QueryRow("SELECT file FROM pragma_database_list WHERE name = 'main'").Scan(&path)
+	if err != nil {
+		return "", err
+	}
+
+	return path, nil
+}
+
+// schemaNames returns the names of the tables and views of db and the names
+// of their columns, without duplicates.
+func schemaNames(db *sql.DB) ([]string, error) {
+	rows, err := db.Query("SELECT name FROM sqlite_master WHERE type IN ('table', 'view') AND name NOT LIKE 'sqlite_%'")
+	if err != nil {
+		return nil, err
+	}
+
+	tables := []string{}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		tables = append(tables, name)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	names := slices.Clone(tables)
+	for _, table := range tables {
+		rows, err := db.Query("SELECT name FROM pragma_table_info(?)", table)
+		if err != nil {
+			return nil, err
+		}
+
+		for rows.Next() {
+			var column string
+			if err := rows.Scan(&column); err != nil {
+				rows.Close()
+				return nil, err
+			}
+
+			if !slices.Contains(names, column) {
+				names = append(names, column)
+			}
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	return names, nil
+}
@@ -36,8 +36,10 @@ type RequestResponseViewer struct {
 	respLabel *widget.Label
 
 	rightSelected bool
+	visualActive  bool
 
 	ShowToastMessageFunc func(string)
+	ShowToastErrorFunc   func(string)
 }
 
 // NewRequestResponseViewer creates a new viewer widget
@@ -145,7 +147,47 @@ func (v *RequestResponseViewer) MoveDown() {
 	v.leftLinesList.MoveDown()
 }
 
+func (v *RequestResponseViewer) MovePageUp() {
+	v.selectedLinesList().MovePageUp()
+}
+
+func (v *RequestResponseViewer) MovePageDown() {
+	v.selectedLinesList().MovePageDown()
+}
+
+func (v *RequestResponseViewer) MoveHalfPageUp() {
+	v.selectedLinesList().MoveHalfPageUp()
+}
+
+func (v *RequestResponseViewer) MoveHalfPageDown() {
+	v.selectedLinesList().MoveHalfPageDown()
+}
+
+func (v *RequestResponseViewer) MoveTop() {
+	v.selectedLinesList().MoveTop()
+}
+
+func (v *RequestResponseViewer) MoveBottom() {
+	v.selectedLinesList().MoveBottom()
+}
+
+func (v *RequestResponseViewer) MoveFirstColumn() {}
+
+func (v *RequestResponseViewer) MoveLastColumn() {}
+
+func (v *RequestResponseViewer) selectedLinesList() *LinesList {
+	if v.rightSelected {
+		return v.rightLinesList
+	}
+	return v.leftLinesList
+}
+
 func (v *RequestResponseViewer) MoveLeft() {
+	if v.visualActive && v.rightSelected {
+		v.rightLinesList.VisualStop()
+		v.leftLinesList.VisualStart()
+	}
+
 	v.rightSelected = false
 	v.reqLabel.TextStyle = fyne.TextStyle{Bold: true}
 	v.respLabel.TextStyle = fyne.TextStyle{Bold: false}
@@ -154,6 +196,11 @@ func (v *RequestResponseViewer) MoveLeft() {
 }
 
 func (v *RequestResponseViewer) MoveRight() {
+	if v.visualActive && !v.rightSelected {
+		v.leftLinesList.VisualStop()
+		v.rightLinesList.VisualStart()
+	}
+
 	v.rightSelected = true
 	v.reqLabel.TextStyle = fyne.TextStyle{Bold: false}
 	v.respLabel.TextStyle = fyne.TextStyle{Bold: true}
@@ -161,6 +208,34 @@ func (v *RequestResponseViewer) MoveRight() {
 	v.respLabel.Refresh()
 }
 
+// VisualStart starts selecting lines of the selected side. Moving to the
+// other side starts a new selection there.
+func (v *RequestResponseViewer) VisualStart() {
+	v.visualActive = true
+	v.selectedLinesList().VisualStart()
+}
+
+func (v *RequestResponseViewer) VisualStop() {
+	v.visualActive = false
+	v.selectedLinesList().VisualStop()
+}
+
+func (v *RequestResponseViewer) SelectedText() string {
+	return v.selectedLinesList().SelectedText()
+}
+
+func (v *RequestResponseViewer) RowsText(count int) string {
+	return v.selectedLinesList().RowsText(count)
+}
+
+func (v *RequestResponseViewer) RowCount() int {
+	return v.selectedLinesList().RowCount()
+}
+
+func (v *RequestResponseViewer) CurrentRow() int {
+	return v.selectedLinesList().CurrentRow()
+}
+
 func (v *RequestResponseViewer) SetKeyBindings(kbs *KeyBindings) {
 	v.keyBindings = kbs
 }
@@ -175,7 +250,8 @@ func (v *RequestResponseViewer) TypedKey(ev *fyne.KeyEvent) {
 	}
 }
 
-func (v *RequestResponseViewer) TypedRune(rune) {
+func (v *RequestResponseViewer) TypedRune(r rune) {
+	v.keyBindings.OnTypedRune(r)
 }
 
 func (v *RequestResponseViewer) TypedShortcut(sc fyne.Shortcut) {
@@ -184,6 +260,17 @@ func (v *RequestResponseViewer) TypedShortcut(sc fyne.Shortcut) {
 	}
 }
 
+// showError shows message with ShowToastErrorFunc, or logs it if it is not
+// set.
+func (v *RequestResponseViewer) showError(message string) {
+	if v.ShowToastErrorFunc == nil {
+		log.Print(message)
+		return
+	}
+
+	v.ShowToastErrorFunc("ERROR: " + message)
+}
+
 func (v *RequestResponseViewer) MessageHandle(m Message) {
 	messageStr, ok := m.(string)
 	if !ok {
@@ -202,19 +289,19 @@ func (v *RequestResponseViewer) MessageHandle(m Message) {
 		scriptTpl := templates.GetRequestTestifierScript()
 		t, err := template.New("make_request").Funcs(funcs).Parse(scriptTpl)
 		if err != nil {
-			log.Printf("could not copy request script: %v", err)
+			v.showError(fmt.Sprintf("could not copy request script: %v", err))
 			return
 		}
 
 		f := &strings.Builder{}
 
 		if err := t.Execute(f, v.request); err != nil {
-			log.Printf("could execute request script template: %v", err)
+			v.showError(fmt.Sprintf("could not execute request script template: %v", err))
 			return
 		}
 
 		if err := copyToClipboard(f.String()); err != nil {
-			log.Printf("could not copy request script to clipboard: %v", err)
+			v.showError(fmt.Sprintf("could not copy request script to clipboard: %v", err))
 			return
 		}
 
@@ -224,7 +311,10 @@ func (v *RequestResponseViewer) MessageHandle(m Message) {
 
 	case RequestResponseViewerMessageCopyRequest:
 		reqBytes := v.request.Raw()
-		copyToClipboard(string(reqBytes))
+		if err := copyToClipboard(string(reqBytes)); err != nil {
+			v.showError(fmt.Sprintf("could not copy request to clipboard: %v", err))
+			return
+		}
 
 		if v.ShowToastMessageFunc != nil {
 			v.ShowToastMessageFunc("Request copied to clipboard")
@@ -232,7 +322,10 @@ func (v *RequestResponseViewer) MessageHandle(m Message) {
 
 	case RequestResponseViewerMessageCopyResponse:
 		respBytes := v.response.Raw()
-		copyToClipboard(string(respBytes))
+		if err := copyToClipboard(string(respBytes)); err != nil {
+			v.showError(fmt.Sprintf("could not copy response to clipboard: %v", err))
+			return
+		}
 
 		if v.ShowToastMessageFunc != nil {
 			v.ShowToastMessageFunc("Response copied to clipboard")
@@ -0,0 +1,305 @@
+package efinui
+
+import (
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	ScratchBufferMessageRunBuffer    = "scratch_buffer_run_buffer"
+	ScratchBufferMessageRunParagraph = "scratch_buffer_run_paragraph"
+)
+
+const (
+	ScratchLanguageLua = "lua"
+	ScratchLanguageSQL = "sql"
+)
+
+// ScratchBuffer is a multi-line editor where Lua or SQL code is written and
+// run, with the output of the last run shown below it. Text is typed in
+// insert mode, the other modes only move the cursor.
+type ScratchBuffer struct {
+	widget.BaseWidget
+
+	Language string
+
+	// OnRun runs code written in language and returns its output.
+	OnRun func(language, code string) (string, error)
+
+	editor *scratchEditor
+	output *widget.Label
+
+	keyBindings *KeyBindings
+	inserting   bool
+
+	container *container.Split
+}
+
+// scratchEditor is the editor of a ScratchBuffer. The keys it receives when
+// it is focused by a tap are handled by the buffer, like the ones the buffer
+// receives itself.
+type scratchEditor struct {
+	widget.Entry
+
+	buffer *ScratchBuffer
+}
+
+func (se *scratchEditor) TypedRune(r rune) {
+	se.buffer.TypedRune(r)
+}
+
+func (se *scratchEditor) TypedKey(ev *fyne.KeyEvent) {
+	se.buffer.TypedKey(ev)
+}
+
+func (se *scratchEditor) TypedShortcut(sc fyne.Shortcut) {
+	se.buffer.TypedShortcut(sc)
+}
+
+func NewScratchBuffer(language string) *ScratchBuffer {
+	sb := &ScratchBuffer{
+		Language: language,
+		output:   widget.NewLabel(""),
+	}
+
+	sb.editor = &scratchEditor{buffer: sb}
+	sb.editor.MultiLine = true
+	sb.editor.Wrapping = fyne.TextWrapOff
+	sb.editor.TextStyle = fyne.TextStyle{Monospace: true}
+	sb.editor.SetPlaceHolder(strings.ToUpper(language) + " scratch buffer")
+	sb.editor.ExtendBaseWidget(sb.editor)
+
+	sb.output.Wrapping = fyne.TextWrapWord
+	sb.output.TextStyle = fyne.TextStyle{Monospace: true}
+
+	sb.container = container.NewVSplit(sb.editor, container.NewVScroll(sb.output))
+	sb.container.Offset = 0.7
+
+	sb.ExtendBaseWidget(sb)
+
+	return sb
+}
+
+func (sb *ScratchBuffer) CreateRenderer() fyne.WidgetRenderer {
+	return widget.NewSimpleRenderer(sb.container)
+}
+
+// Text returns the code written in the buffer.
+func (sb *ScratchBuffer) Text() string {
+	return sb.editor.Text
+}
+
+// RunBuffer runs all the code of the buffer.
+func (sb *ScratchBuffer) RunBuffer() {
+	sb.run(sb.editor.Text)
+}
+
+// RunParagraph runs the lines around the cursor, up to the closest blank
+// lines.
+func (sb *ScratchBuffer) RunParagraph() {
+	sb.run(sb.paragraph())
+}
+
+func (sb *ScratchBuffer) run(code string) {
+	if sb.OnRun == nil || strings.TrimSpace(code) == "" {
+		return
+	}
+
+	output, err := sb.OnRun(sb.Language, code)
+	if err != nil {
+		if output != "" {
+			output += "\n"
+		}
+		output += "ERROR: " + err.Error()
+		sb.output.Importance = widget.DangerImportance
+	} else {
+		sb.output.Importance = widget.MediumImportance
+	}
+
+	sb.output.SetText(output)
+}
+
+func (sb *ScratchBuffer) paragraph() string {
+	lines := strings.Split(sb.editor.Text, "\n")
+
+	row := min(max(0, sb.editor.CursorRow), len(lines)-1)
+	if strings.TrimSpace(lines[row]) == "" {
+		return ""
+	}
+
+	first := row
+	for first > 0 && strings.TrimSpace(lines[first-1]) != "" {
+		first--
+	}
+
+	last := row
+	for last < len(lines)-1 && strings.TrimSpace(lines[last+1]) != "" {
+		last++
+	}
+
+	return strings.Join(lines[first:last+1], "\n")
+}
+
+func (sb *ScratchBuffer) InsertStart() {
+	sb.inserting = true
+}
+
+func (sb *ScratchBuffer) InsertStop() {
+	sb.inserting = false
+}
+
+// AcceptsTab makes tab indent the code in insert mode instead of moving the
+// focus.
+func (sb *ScratchBuffer) AcceptsTab() bool {
+	return sb.inserting
+}
+
+func (sb *ScratchBuffer) FocusGained() {
+	sb.editor.FocusGained()
+}
+
+func (sb *ScratchBuffer) FocusLost() {
+	sb.editor.FocusLost()
+}
+
+func (sb *ScratchBuffer) SetKeyBindings(kbs *KeyBindings) {
+	sb.keyBindings = kbs
+}
+
+func (sb *ScratchBuffer) WidgetName() string {
+	return "scratch_buffer"
+}
+
+func (sb *ScratchBuffer) TypedKey(ev *fyne.KeyEvent) {
+	if ok := sb.keyBindings.OnTypedKey(ev); ok {
+		return
+	}
+
+	if sb.inserting || isCursorKey(ev.Name) {
+		sb.editor.Entry.TypedKey(ev)
+	}
+}
+
+func (sb *ScratchBuffer) TypedRune(r rune) {
+	if sb.inserting {
+		sb.editor.Entry.TypedRune(r)
+		return
+	}
+
+	sb.keyBindings.OnTypedRune(r)
+}
+
+func (sb *ScratchBuffer) TypedShortcut(sc fyne.Shortcut) {
+	if ok := sb.keyBindings.OnTypedShortcut(sc); ok {
+		return
+	}
+
+	// Shortcuts like paste and cut edit the text, which is only done in
+	// insert mode
+	if _, isCopy := sc.(*fyne.ShortcutCopy); sb.inserting || isCopy {
+		sb.editor.Entry.TypedShortcut(sc)
+	}
+}
+
+// isCursorKey returns true if key only moves the cursor of an entry.
+func isCursorKey(key fyne.KeyName) bool {
+	switch key {
+	case fyne.KeyUp, fyne.KeyDown, fyne.KeyLeft, fyne.KeyRight,
+		fyne.KeyHome, fyne.KeyEnd, fyne.KeyPageUp, fyne.KeyPageDown:
+		return true
+	}
+
+	return false
+}
+
+func (sb *ScratchBuffer) MoveUp() {
+	sb.moveRows(-1)
+}
+
+func (sb *ScratchBuffer) MoveDown() {
+	sb.moveRows(1)
+}
+
+func (sb *ScratchBuffer) MoveLeft() {
+	sb.editor.Entry.TypedKey(&fyne.KeyEvent{Name: fyne.KeyLeft})
+}
+
+func (sb *ScratchBuffer) MoveRight() {
+	sb.editor.Entry.TypedKey(&fyne.KeyEvent{Name: fyne.KeyRight})
+}
+
+func (sb *ScratchBuffer) MovePageUp() {
+	sb.moveRows(-sb.pageSize())
+}
+
+func (sb *ScratchBuffer) MovePageDown() {
+	sb.moveRows(sb.pageSize())
+}
+
+func (sb *ScratchBuffer) MoveHalfPageUp() {
+	sb.moveRows(-max(1, sb.pageSize()/2))
+}
+
+func (sb *ScratchBuffer) MoveHalfPageDown() {
+	sb.moveRows(max(1, sb.pageSize()/2))
+}
+
+func (sb *ScratchBuffer) MoveTop() {
+	sb.editor.Entry.TypedKey(&fyne.KeyEvent{Name: fyne.KeyPageUp})
+}
+
+func (sb *ScratchBuffer) MoveBottom() {
+	sb.editor.Entry.TypedKey(&fyne.KeyEvent{Name: fyne.KeyPageDown})
+}
+
+func (sb *ScratchBuffer) MoveFirstColumn() {
+	sb.editor.Entry.TypedKey(&fyne.KeyEvent{Name: fyne.KeyHome})
+}
+
+func (sb *ScratchBuffer) MoveLastColumn() {
+	sb.editor.Entry.TypedKey(&fyne.KeyEvent{Name: fyne.KeyEnd})
+}
+
+// moveRows moves the cursor delta lines down, or up if delta is negative.
+func (sb *ScratchBuffer) moveRows(delta int) {
+	key := fyne.KeyDown
+	if delta < 0 {
+		key = fyne.KeyUp
+		delta = -delta
+	}
+
+	for range delta {
+		sb.editor.Entry.TypedKey(&fyne.KeyEvent{Name: key})
+	}
+}
+
+// pageSize returns the number of lines that fit in the editor.
+func (sb *ScratchBuffer) pageSize() int {
+	lineHeight := fyne.MeasureText("M", sb.Theme().Size(theme.SizeNameText), sb.editor.TextStyle).Height
+
+	return max(1, int(sb.editor.Size().Height/lineHeight))
+}
+
+// Submit runs the paragraph under the cursor.
+func (sb *ScratchBuffer) Submit() {
+	sb.RunParagraph()
+}
+
+func (sb *ScratchBuffer) MessageHandle(m Message) {
+	messageStr, ok := m.(string)
+	if !ok {
+		return
+	}
+
+	switch messageStr {
+	case ScratchBufferMessageRunBuffer:
+		sb.RunBuffer()
+
+	case ScratchBufferMessageRunParagraph:
+		sb.RunParagraph()
+	}
+}
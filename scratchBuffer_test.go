@@ -0,0 +1,81 @@
+package efinui
+
+import (
+	"database/sql"
+	"slices"
+	"testing"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/test"
+	lua "github.com/yuin/gopher-lua"
+)
+
+func TestScratchBufferParagraph(t *testing.T) {
+	test.NewTempApp(t)
+
+	text := "a = 1\nb = 2\n\nprint(a)\n  \nprint(b)\nprint(a + b)"
+
+	tests := []struct {
+		name string
+		text string
+		row  int
+		want string
+	}{
+		{name: "first paragraph", text: text, row: 0, want: "a = 1\nb = 2"},
+		{name: "last line of a paragraph", text: text, row: 1, want: "a = 1\nb = 2"},
+		{name: "blank line", text: text, row: 2, want: ""},
+		{name: "single line", text: text, row: 3, want: "print(a)"},
+		{name: "line of spaces", text: text, row: 4, want: ""},
+		{name: "last paragraph", text: text, row: 6, want: "print(b)\nprint(a + b)"},
+		{name: "row past the end", text: text, row: 10, want: "print(b)\nprint(a + b)"},
+		{name: "empty buffer", text: "", row: 0, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sb := NewScratchBuffer(ScratchLanguageLua)
+			sb.editor.SetText(tt.text)
+			sb.editor.CursorRow = tt.row
+
+			if got := sb.paragraph(); got != tt.want {
+				t.Errorf("paragraph() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScratchBufferRunQueryKeepsBuffer(t *testing.T) {
+	test.NewTempApp(t)
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	tab := NewMultiSplit()
+	a := &App{
+		db:         db,
+		l:          lua.NewState(),
+		tabs:       []*MultiSplit{tab},
+		statusLine: NewStatusLine(),
+		tabBar:     NewTabBar(),
+	}
+	t.Cleanup(a.l.Close)
+
+	sb := NewScratchBuffer(ScratchLanguageSQL)
+	tab.PaneCreate(sb)
+
+	if _, err := a.scratchRun(ScratchLanguageSQL, "SELECT 1 AS x"); err != nil {
+		t.Fatalf("scratchRun() error = %v", err)
+	}
+
+	panes := slices.Concat(tab.objectsGrid...)
+	if !slices.Contains(panes, fyne.CanvasObject(sb)) {
+		t.Errorf("scratch buffer replaced by the query results")
+	}
+
+	if _, ok := tab.focusedPane().(*Table); !ok || len(panes) != 2 {
+		t.Errorf("panes = %v, want the scratch buffer and the focused query results", panes)
+	}
+}
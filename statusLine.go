@@ -0,0 +1,59 @@
+package efinui
+
+import (
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+const statusLineSeparator = " | "
+
+// StatusLineSegment is a piece of information shown in the status line.
+type StatusLineSegment struct {
+	Text       string
+	AlignRight bool
+}
+
+type StatusLine struct {
+	widget.BaseWidget
+
+	leftLabel  *widget.Label
+	rightLabel *widget.Label
+}
+
+func NewStatusLine() *StatusLine {
+	sl := &StatusLine{
+		leftLabel:  widget.NewLabel(""),
+		rightLabel: widget.NewLabel(""),
+	}
+	sl.leftLabel.Truncation = fyne.TextTruncateEllipsis
+
+	sl.ExtendBaseWidget(sl)
+
+	return sl
+}
+
+func (sl *StatusLine) CreateRenderer() fyne.WidgetRenderer {
+	return widget.NewSimpleRenderer(container.NewBorder(nil, nil, nil, sl.rightLabel, sl.leftLabel))
+}
+
+func (sl *StatusLine) SetSegments(segments []StatusLineSegment) {
+	left := []string{}
+	right := []string{}
+	for _, s := range segments {
+		if s.Text == "" {
+			continue
+		}
+
+		if s.AlignRight {
+			right = append(right, s.Text)
+		} else {
+			left = append(left, s.Text)
+		}
+	}
+
+	sl.leftLabel.SetText(strings.Join(left, statusLineSeparator))
+	sl.rightLabel.SetText(strings.Join(right, statusLineSeparator))
+}
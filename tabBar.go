@@ -0,0 +1,81 @@
+package efinui
+
+import (
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+const tabNameMaxLength = 30
+
+// TabBar shows the name of every tab and highlights the active one.
+type TabBar struct {
+	widget.BaseWidget
+
+	names       []string
+	activeIndex int
+
+	content *fyne.Container
+}
+
+func NewTabBar() *TabBar {
+	tb := &TabBar{
+		content: container.NewHBox(),
+	}
+
+	tb.ExtendBaseWidget(tb)
+
+	return tb
+}
+
+func (tb *TabBar) CreateRenderer() fyne.WidgetRenderer {
+	return widget.NewSimpleRenderer(container.NewHScroll(tb.content))
+}
+
+func (tb *TabBar) SetTabs(names []string, activeIndex int) {
+	tb.names = names
+	tb.activeIndex = activeIndex
+	tb.updateContent()
+}
+
+func (tb *TabBar) updateContent() {
+	thm := tb.Theme()
+	activeColor := thm.Color(theme.ColorNameSelection, theme.VariantDark)
+
+	objects := make([]fyne.CanvasObject, len(tb.names))
+	for i, name := range tb.names {
+		if name == "" {
+			name = "empty"
+		}
+
+		label := widget.NewLabel(fmt.Sprintf("%d: %s", i+1, truncateTabName(name)))
+
+		if i == tb.activeIndex {
+			label.TextStyle = fyne.TextStyle{Bold: true}
+			objects[i] = container.NewStack(canvas.NewRectangle(activeColor), label)
+		} else {
+			objects[i] = label
+		}
+	}
+
+	tb.content.Objects = objects
+	tb.content.Refresh()
+}
+
+func (tb *TabBar) Refresh() {
+	tb.updateContent()
+	tb.BaseWidget.Refresh()
+}
+
+func truncateTabName(name string) string {
+	runes := []rune(name)
+	if len(runes) <= tabNameMaxLength {
+		return name
+	}
+
+	return string(runes[:tabNameMaxLength-1]) + "…"
+}
@@ -1,12 +1,15 @@
 package efinui
 
 import (
+	"fmt"
 	"log"
 	"strings"
 
 	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
 )
 
@@ -14,6 +17,10 @@ const (
 	TableMessageCopyRow = "table_copy_row"
 )
 
+// TableMaxSubmittedRows is the number of selected rows that can be submitted
+// at once, as each of them opens a pane.
+const TableMaxSubmittedRows = 10
+
 type Table struct {
 	widget.BaseWidget
 
@@ -22,6 +29,11 @@ type Table struct {
 	selectedRow    int
 	selectedColumn int
 
+	// visualAnchor is the row where the visual selection started, the
+	// selected rows go from it to selectedRow.
+	visualAnchor int
+	visualActive bool
+
 	contentsIndex int
 	headers       []string
 	rows          [][]string
@@ -34,6 +46,7 @@ type Table struct {
 	keyBindings *KeyBindings
 
 	ShowToastMessageFunc func(string)
+	ShowToastErrorFunc   func(string)
 
 	OnSubmit func([]string)
 }
@@ -82,11 +95,26 @@ func (t *Table) length() (int, int) {
 func (t *Table) create() fyne.CanvasObject {
 	l := widget.NewLabel("")
 	l.Wrapping = fyne.TextTruncate
-	return l
+
+	highlight := canvas.NewRectangle(t.Theme().Color(theme.ColorNameSelection, theme.VariantDark))
+	highlight.Hide()
+
+	return container.NewStack(highlight, l)
 }
 
 func (t *Table) update(i widget.TableCellID, o fyne.CanvasObject) {
-	o.(*widget.Label).SetText(t.rows[i.Row][i.Col])
+	c := o.(*fyne.Container)
+
+	highlight := c.Objects[0].(*canvas.Rectangle)
+	if t.isRowSelected(i.Row) {
+		highlight.FillColor = t.Theme().Color(theme.ColorNameSelection, theme.VariantDark)
+		highlight.Show()
+	} else {
+		highlight.Hide()
+	}
+	highlight.Refresh()
+
+	c.Objects[1].(*widget.Label).SetText(t.rows[i.Row][i.Col])
 }
 
 func (t *Table) createHeader() fyne.CanvasObject {
@@ -210,6 +238,63 @@ func (t *Table) MoveRight() {
 	t.updateSelectedRow()
 }
 
+func (t *Table) MovePageUp() {
+	t.moveRows(-t.pageSize())
+}
+
+func (t *Table) MovePageDown() {
+	t.moveRows(t.pageSize())
+}
+
+func (t *Table) MoveHalfPageUp() {
+	t.moveRows(-max(1, t.pageSize()/2))
+}
+
+func (t *Table) MoveHalfPageDown() {
+	t.moveRows(max(1, t.pageSize()/2))
+}
+
+func (t *Table) MoveTop() {
+	t.moveRows(-len(t.rows))
+}
+
+func (t *Table) MoveBottom() {
+	t.moveRows(len(t.rows))
+}
+
+func (t *Table) MoveFirstColumn() {
+	t.selectedColumn = 0
+
+	t.updateSelectedRow()
+}
+
+func (t *Table) MoveLastColumn() {
+	_, cols := t.table.Length()
+	t.selectedColumn = max(0, cols-1)
+
+	t.updateSelectedRow()
+}
+
+// moveRows moves the selection delta rows down, or up if delta is negative,
+// without going past the first or last row.
+func (t *Table) moveRows(delta int) {
+	if len(t.rows) == 0 {
+		return
+	}
+
+	t.selectedRow = min(max(0, t.selectedRow+delta), len(t.rows)-1)
+
+	t.updateSelectedRow()
+}
+
+// pageSize returns the number of rows that fit in the table.
+func (t *Table) pageSize() int {
+	rowHeight := t.create().MinSize().Height + t.Theme().Size(theme.SizeNameSeparatorThickness)
+
+	// One of the rows is taken by the header
+	return max(1, int(t.table.Size().Height/rowHeight)-1)
+}
+
 func (t *Table) updateSelectedRow() {
 	tcid := widget.TableCellID{
 		Row: t.selectedRow,
@@ -220,9 +305,95 @@ func (t *Table) updateSelectedRow() {
 	t.table.Refresh()
 }
 
+// VisualStart starts selecting rows from the current one. Moving up or down
+// extends the selection.
+func (t *Table) VisualStart() {
+	t.visualAnchor = t.selectedRow
+	t.visualActive = true
+
+	t.table.Refresh()
+}
+
+func (t *Table) VisualStop() {
+	t.visualActive = false
+
+	t.table.Refresh()
+}
+
+// selectedRows returns the indexes of the rows selected in visual mode, or
+// the current row if there is no visual selection.
+func (t *Table) selectedRows() []int {
+	if len(t.rows) == 0 {
+		return nil
+	}
+
+	if !t.visualActive {
+		return []int{t.selectedRow}
+	}
+
+	rows := []int{}
+	for i := min(t.visualAnchor, t.selectedRow); i <= max(t.visualAnchor, t.selectedRow); i++ {
+		rows = append(rows, i)
+	}
+
+	return rows
+}
+
+// SelectedText returns the selected rows, one per line, with their columns
+// separated by tabs.
+func (t *Table) SelectedText() string {
+	return t.rowsText(t.selectedRows())
+}
+
+// RowsText returns count rows from the current one, without selecting them,
+// formatted like SelectedText.
+func (t *Table) RowsText(count int) string {
+	rows := []int{}
+	for i := t.selectedRow; i < len(t.rows) && i < t.selectedRow+max(1, count); i++ {
+		rows = append(rows, i)
+	}
+
+	return t.rowsText(rows)
+}
+
+func (t *Table) rowsText(rows []int) string {
+	rowStrs := make([]string, len(rows))
+	for i, row := range rows {
+		rowStrs[i] = strings.Join(t.rows[row], "\t")
+	}
+
+	return strings.Join(rowStrs, "\n")
+}
+
+func (t *Table) isRowSelected(row int) bool {
+	return t.visualActive &&
+		row >= min(t.visualAnchor, t.selectedRow) &&
+		row <= max(t.visualAnchor, t.selectedRow)
+}
+
+func (t *Table) RowCount() int {
+	return len(t.rows)
+}
+
+func (t *Table) CurrentRow() int {
+	return t.selectedRow
+}
+
 func (t *Table) Submit() {
-	if t.OnSubmit != nil && len(t.rows) > t.selectedRow {
-		t.OnSubmit(t.rows[t.selectedRow])
+	if t.OnSubmit == nil {
+		return
+	}
+
+	rows := t.selectedRows()
+	if len(rows) > TableMaxSubmittedRows {
+		if t.ShowToastMessageFunc != nil {
+			t.ShowToastMessageFunc(fmt.Sprintf("%d rows selected, at most %d can be opened at once", len(rows), TableMaxSubmittedRows))
+		}
+		return
+	}
+
+	for _, i := range rows {
+		t.OnSubmit(t.rows[i])
 	}
 }
 
@@ -242,7 +413,8 @@ func (t *Table) TypedKey(ev *fyne.KeyEvent) {
 	t.table.TypedKey(ev)
 }
 
-func (t *Table) TypedRune(rune) {
+func (t *Table) TypedRune(r rune) {
+	t.keyBindings.OnTypedRune(r)
 }
 
 func (t *Table) TypedShortcut(sc fyne.Shortcut) {
@@ -251,6 +423,17 @@ func (t *Table) TypedShortcut(sc fyne.Shortcut) {
 	}
 }
 
+// showError shows message with ShowToastErrorFunc, or logs it if it is not
+// set.
+func (t *Table) showError(message string) {
+	if t.ShowToastErrorFunc == nil {
+		log.Print(message)
+		return
+	}
+
+	t.ShowToastErrorFunc("ERROR: " + message)
+}
+
 func (t *Table) MessageHandle(m Message) {
 	messageStr, ok := m.(string)
 	if !ok || len(t.rows) == 0 {
@@ -259,15 +442,20 @@ func (t *Table) MessageHandle(m Message) {
 
 	switch messageStr {
 	case TableMessageCopyRow:
-		rowStr := strings.Join(t.rows[t.selectedRow], "\t")
-		err := copyToClipboard(rowStr)
+		rows := t.selectedRows()
+
+		err := copyToClipboard(t.SelectedText())
 		if err != nil {
-			log.Printf("could not copy row to clipboard: %v", err)
+			t.showError(fmt.Sprintf("could not copy row to clipboard: %v", err))
 			return
 		}
 
 		if t.ShowToastMessageFunc != nil {
-			t.ShowToastMessageFunc("Row copied to clipboard")
+			if len(rows) == 1 {
+				t.ShowToastMessageFunc("Row copied to clipboard")
+			} else {
+				t.ShowToastMessageFunc(fmt.Sprintf("%d rows copied to clipboard", len(rows)))
+			}
 		}
 	}
 }
@@ -2,6 +2,7 @@ package efinui
 
 import (
 	"image/color"
+	"slices"
 	"sync"
 	"time"
 
@@ -9,13 +10,50 @@ import (
 	"fyne.io/fyne/v2/canvas"
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
 )
 
+const (
+	DefaultToastDuration   = 3 * time.Second
+	DefaultToastMaxVisible = 5
+)
+
+// toastColorNames holds the theme color of the background of the toasts of
+// every level.
+var toastColorNames = map[MessageLevel]fyne.ThemeColorName{
+	MessageLevelInfo:    theme.ColorNamePrimary,
+	MessageLevelSuccess: theme.ColorNameSuccess,
+	MessageLevelWarning: theme.ColorNameWarning,
+	MessageLevelError:   theme.ColorNameError,
+}
+
+// ToastOptions configures how a toast is shown.
+type ToastOptions struct {
+	Level MessageLevel
+
+	// Duration is how long the toast is shown, or the duration of the
+	// ToastSet if it is zero.
+	Duration time.Duration
+
+	// Sticky toasts are shown until they are dismissed.
+	Sticky bool
+
+	// Action is called when the button labeled ActionLabel is pressed, which
+	// dismisses the toast. There is no button if Action is nil.
+	Action      func()
+	ActionLabel string
+}
+
 type Toast struct {
 	widget.BaseWidget
 
+	id      int
 	message string
+	options ToastOptions
+
+	// OnDismiss is called when the toast is tapped or its action is run.
+	OnDismiss func()
 
 	bg    *canvas.Rectangle
 	label *widget.Label
@@ -23,11 +61,13 @@ type Toast struct {
 	content *fyne.Container
 }
 
-func NewToast(message string, bgColor color.Color) *Toast {
+func NewToast(id int, message string, options ToastOptions) *Toast {
 	t := &Toast{
+		id:      id,
 		message: message,
+		options: options,
 		label:   widget.NewLabel(message),
-		bg:      canvas.NewRectangle(bgColor),
+		bg:      canvas.NewRectangle(color.Transparent),
 	}
 	t.ExtendBaseWidget(t)
 
@@ -35,8 +75,53 @@ func NewToast(message string, bgColor color.Color) *Toast {
 }
 
 func (t *Toast) CreateRenderer() fyne.WidgetRenderer {
-	content := container.NewStack(t.bg, t.label)
-	return widget.NewSimpleRenderer(content)
+	t.bg.FillColor = t.backgroundColor()
+
+	content := container.NewHBox(t.label)
+	if t.options.Action != nil {
+		content.Add(widget.NewButton(t.options.ActionLabel, t.RunAction))
+	}
+
+	return widget.NewSimpleRenderer(container.NewStack(t.bg, content))
+}
+
+// backgroundColor returns the theme color of the level of the toast in the
+// variant of the app, made slightly transparent.
+func (t *Toast) backgroundColor() color.Color {
+	variant := theme.VariantDark
+	if app := fyne.CurrentApp(); app != nil {
+		variant = app.Settings().ThemeVariant()
+	}
+
+	r, g, b, _ := t.Theme().Color(toastColorNames[t.options.Level], variant).RGBA()
+
+	return color.NRGBA{R: uint8(r >> 8), G: uint8(g >> 8), B: uint8(b >> 8), A: 200}
+}
+
+// RunAction calls the action of the toast, if it has one, and dismisses it.
+func (t *Toast) RunAction() {
+	if t.options.Action == nil {
+		return
+	}
+
+	t.options.Action()
+	t.dismiss()
+}
+
+func (t *Toast) Tapped(*fyne.PointEvent) {
+	t.dismiss()
+}
+
+func (t *Toast) dismiss() {
+	if t.OnDismiss != nil {
+		t.OnDismiss()
+	}
+}
+
+func (t *Toast) Refresh() {
+	t.bg.FillColor = t.backgroundColor()
+
+	t.BaseWidget.Refresh()
 }
 
 type ToastSet struct {
@@ -46,16 +131,27 @@ type ToastSet struct {
 
 	content *fyne.Container
 
-	duration time.Duration
+	// toasts holds the toasts shown, from the oldest to the newest.
+	toasts []*Toast
+	nextID int
+
+	// Duration is how long toasts are shown, unless they set their own.
+	Duration time.Duration
+
+	// MaxVisible is the number of toasts shown at most. When a toast is added
+	// past it, the oldest toast that is not sticky is removed.
+	MaxVisible int
 }
 
 func NewToastSet() *ToastSet {
 	ts := &ToastSet{
-		lock: sync.Mutex{},
-		content: container.NewVBox(
-			layout.NewSpacer(),
-		),
-		duration: 3 * time.Second,
+		lock:    sync.Mutex{},
+		content: container.NewVBox(),
+
+		Duration:   DefaultToastDuration,
+		MaxVisible: DefaultToastMaxVisible,
+
+		nextID: 1,
 	}
 
 	ts.ExtendBaseWidget(ts)
@@ -67,41 +163,105 @@ func (ts *ToastSet) CreateRenderer() fyne.WidgetRenderer {
 	return widget.NewSimpleRenderer(ts.content)
 }
 
-func (ts *ToastSet) CreateToastMessage(message string) {
-	ts.createToast(message, color.RGBA{R: 30, G: 80, B: 100, A: 200})
+func (ts *ToastSet) CreateToastMessage(message string) int {
+	return ts.CreateToast(message, ToastOptions{Level: MessageLevelInfo})
 }
 
-func (ts *ToastSet) CreateToastError(message string) {
-	ts.createToast(message, color.RGBA{R: 150, G: 30, B: 50, A: 200})
+func (ts *ToastSet) CreateToastError(message string) int {
+	return ts.CreateToast(message, ToastOptions{Level: MessageLevelError})
 }
 
-func (ts *ToastSet) createToast(message string, bgColor color.Color) {
+// CreateToast shows a toast and returns its id.
+func (ts *ToastSet) CreateToast(message string, options ToastOptions) int {
 	ts.lock.Lock()
 	defer ts.lock.Unlock()
 
-	toast := container.NewHBox(layout.NewSpacer(), NewToast(message, bgColor))
-	newObjects := append([]fyne.CanvasObject{}, ts.content.Objects[:len(ts.content.Objects)-1]...)
-	newObjects = append(newObjects, toast, layout.NewSpacer())
+	toast := NewToast(ts.nextID, message, options)
+	ts.nextID++
 
-	ts.content.Objects = newObjects
-	ts.content.Refresh()
+	toast.OnDismiss = func() {
+		ts.Dismiss(toast.id)
+	}
 
-	time.AfterFunc(ts.duration, func() {
-		fyne.Do(func() {
-			ts.lock.Lock()
-			defer ts.lock.Unlock()
+	ts.toasts = append(ts.toasts, toast)
 
-			newObjects := []fyne.CanvasObject{}
-			for _, o := range ts.content.Objects {
-				if o == toast {
-					continue
-				}
+	for ts.MaxVisible > 0 && len(ts.toasts) > ts.MaxVisible {
+		i := slices.IndexFunc(ts.toasts, func(t *Toast) bool { return !t.options.Sticky })
+		if i < 0 {
+			i = 0
+		}
+
+		ts.toasts = slices.Delete(ts.toasts, i, i+1)
+	}
 
-				newObjects = append(newObjects, o)
-			}
+	ts.updateContent()
 
-			ts.content.Objects = newObjects
-			ts.content.Refresh()
+	if !options.Sticky {
+		duration := options.Duration
+		if duration == 0 {
+			duration = ts.Duration
+		}
+
+		time.AfterFunc(duration, func() {
+			fyne.Do(func() {
+				ts.Dismiss(toast.id)
+			})
 		})
-	})
+	}
+
+	return toast.id
+}
+
+// Dismiss removes the toast with id, if it is still shown.
+func (ts *ToastSet) Dismiss(id int) {
+	ts.lock.Lock()
+	defer ts.lock.Unlock()
+
+	ts.toasts = slices.DeleteFunc(ts.toasts, func(t *Toast) bool { return t.id == id })
+	ts.updateContent()
+}
+
+// DismissAll removes all the toasts, sticky or not.
+func (ts *ToastSet) DismissAll() {
+	ts.lock.Lock()
+	defer ts.lock.Unlock()
+
+	ts.toasts = nil
+	ts.updateContent()
+}
+
+// RunAction runs the action of the toast with id, or of the newest toast with
+// an action if id is zero. It returns false if no such toast is shown.
+func (ts *ToastSet) RunAction(id int) bool {
+	ts.lock.Lock()
+
+	var toast *Toast
+	for _, t := range slices.Backward(ts.toasts) {
+		if t.options.Action != nil && (id == 0 || t.id == id) {
+			toast = t
+			break
+		}
+	}
+
+	// The action may create or dismiss toasts
+	ts.lock.Unlock()
+
+	if toast == nil {
+		return false
+	}
+
+	toast.RunAction()
+
+	return true
+}
+
+func (ts *ToastSet) updateContent() {
+	objects := []fyne.CanvasObject{}
+	for _, t := range ts.toasts {
+		objects = append(objects, container.NewHBox(layout.NewSpacer(), t))
+	}
+	objects = append(objects, layout.NewSpacer())
+
+	ts.content.Objects = objects
+	ts.content.Refresh()
 }
@@ -0,0 +1,118 @@
+package efinui
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2/test"
+)
+
+func TestToastSetMaxVisible(t *testing.T) {
+	test.NewTempApp(t)
+
+	tests := []struct {
+		name       string
+		maxVisible int
+
+		// sticky tells whether each toast created is sticky
+		sticky []bool
+		want   []int
+	}{
+		{
+			name:       "below the limit",
+			maxVisible: 3,
+			sticky:     []bool{false, false},
+			want:       []int{1, 2},
+		},
+		{
+			name:       "oldest removed",
+			maxVisible: 2,
+			sticky:     []bool{false, false, false, false},
+			want:       []int{3, 4},
+		},
+		{
+			name:       "sticky kept",
+			maxVisible: 3,
+			sticky:     []bool{true, false, false, false},
+			want:       []int{1, 3, 4},
+		},
+		{
+			name:       "new sticky toast removes a toast that is not sticky",
+			maxVisible: 2,
+			sticky:     []bool{false, true, true},
+			want:       []int{2, 3},
+		},
+		{
+			name:       "oldest sticky removed when all are sticky",
+			maxVisible: 2,
+			sticky:     []bool{true, true, true},
+			want:       []int{2, 3},
+		},
+		{
+			name:       "no limit",
+			maxVisible: 0,
+			sticky:     []bool{false, false, false, false},
+			want:       []int{1, 2, 3, 4},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := NewToastSet()
+			ts.Duration = time.Hour
+			ts.MaxVisible = tt.maxVisible
+
+			for _, sticky := range tt.sticky {
+				ts.CreateToast("message", ToastOptions{Sticky: sticky})
+			}
+
+			got := []int{}
+			for _, toast := range ts.toasts {
+				got = append(got, toast.id)
+			}
+
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("toasts shown = %v, want %v", got, tt.want)
+			}
+
+			if len(ts.content.Objects) != len(tt.want)+1 {
+				t.Errorf("content has %d objects, want %d toasts and a spacer", len(ts.content.Objects), len(tt.want))
+			}
+		})
+	}
+}
+
+func TestToastSetDismiss(t *testing.T) {
+	test.NewTempApp(t)
+
+	ts := NewToastSet()
+	ts.Duration = time.Hour
+
+	ran := 0
+	first := ts.CreateToast("first", ToastOptions{Sticky: true})
+	second := ts.CreateToast("second", ToastOptions{Action: func() { ran++ }})
+	ts.CreateToast("third", ToastOptions{})
+
+	ts.Dismiss(first)
+	if len(ts.toasts) != 2 {
+		t.Fatalf("%d toasts shown after Dismiss, want 2", len(ts.toasts))
+	}
+
+	if !ts.RunAction(0) || ran != 1 {
+		t.Fatalf("RunAction(0) did not run the action of the newest toast with one")
+	}
+
+	if slices.ContainsFunc(ts.toasts, func(toast *Toast) bool { return toast.id == second }) {
+		t.Errorf("toast %d shown after running its action", second)
+	}
+
+	if ts.RunAction(0) {
+		t.Errorf("RunAction(0) = true without toasts with an action")
+	}
+
+	ts.DismissAll()
+	if len(ts.toasts) != 0 {
+		t.Errorf("%d toasts shown after DismissAll, want 0", len(ts.toasts))
+	}
+}
@@ -0,0 +1,175 @@
+package efinui
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+// userCommandNameRegexp matches valid user command names. They start with an
+// uppercase letter so they can't be confused with Lua code.
+var userCommandNameRegexp = regexp.MustCompile(`^[A-Z][A-Za-z0-9_]*$`)
+
+// UserCommand is a command defined from Lua with command_define, typed in
+// command mode as its name followed by its arguments, like "Host example.com".
+type UserCommand struct {
+	Name string
+
+	// NArgs is the number of arguments accepted: "0", "1", "?" (zero or
+	// one), "*" (any) or "+" (one or more).
+	NArgs string
+
+	fn *lua.LFunction
+}
+
+func NewUserCommand(name string, fn *lua.LFunction, nargs string) (*UserCommand, error) {
+	if !userCommandNameRegexp.MatchString(name) {
+		return nil, fmt.Errorf("invalid command name %q: it must start with an uppercase letter", name)
+	}
+
+	if !slices.Contains([]string{"0", "1", "?", "*", "+"}, nargs) {
+		return nil, fmt.Errorf("invalid nargs %q: it must be one of 0, 1, ?, * or +", nargs)
+	}
+
+	return &UserCommand{
+		Name:  name,
+		NArgs: nargs,
+		fn:    fn,
+	}, nil
+}
+
+// checkArgs returns an error if args is not a valid number of arguments for
+// the command.
+func (uc *UserCommand) checkArgs(args []string) error {
+	valid := true
+	switch uc.NArgs {
+	case "0":
+		valid = len(args) == 0
+	case "1":
+		valid = len(args) == 1
+	case "?":
+		valid = len(args) <= 1
+	case "+":
+		valid = len(args) >= 1
+	}
+
+	if !valid {
+		return fmt.Errorf("%s: wrong number of arguments (%d given, nargs is %s)", uc.Name, len(args), uc.NArgs)
+	}
+
+	return nil
+}
+
+// parseCommandLine splits line into words separated by spaces. Words can be
+// quoted with single or double quotes to include spaces, and a backslash
+// escapes the next character outside single quotes.
+func parseCommandLine(line string) ([]string, error) {
+	words := []string{}
+	word := &strings.Builder{}
+	inWord := false
+	var quote rune
+	escaped := false
+
+	for _, r := range line {
+		switch {
+		case escaped:
+			word.WriteRune(r)
+			escaped = false
+
+		case r == '\\' && quote != '\'':
+			escaped = true
+			inWord = true
+
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+
+		case r == '"' || r == '\'':
+			quote = r
+			inWord = true
+
+		case r == ' ' || r == '\t':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated quote %c", quote)
+	}
+
+	if escaped {
+		return nil, fmt.Errorf("trailing backslash")
+	}
+
+	if inWord {
+		words = append(words, word.String())
+	}
+
+	return words, nil
+}
+
+// userCommandFind returns the user command typed in line, if any.
+func (a *App) userCommandFind(line string) (*UserCommand, bool) {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return nil, false
+	}
+
+	uc, ok := a.userCommands[fields[0]]
+
+	return uc, ok
+}
+
+// userCommandExecute parses the arguments typed after the name of uc in line
+// and calls it with them.
+func (a *App) userCommandExecute(uc *UserCommand, line string) error {
+	words, err := parseCommandLine(line)
+	if err != nil {
+		return err
+	}
+
+	args := words[1:]
+	if err := uc.checkArgs(args); err != nil {
+		return err
+	}
+
+	luaArgs := make([]lua.LValue, len(args))
+	for i, arg := range args {
+		luaArgs[i] = lua.LString(arg)
+	}
+
+	return a.l.CallByParam(lua.P{
+		Fn:      uc.fn,
+		NRet:    0,
+		Protect: true,
+	}, luaArgs...)
+}
+
+// userCommandNames returns the sorted names of the user commands starting
+// with prefix.
+func (a *App) userCommandNames(prefix string) []string {
+	names := []string{}
+	for name := range a.userCommands {
+		if strings.HasPrefix(name, prefix) {
+			names = append(names, name)
+		}
+	}
+
+	slices.Sort(names)
+
+	return names
+}
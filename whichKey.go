@@ -0,0 +1,75 @@
+package efinui
+
+import (
+	"fmt"
+	"image/color"
+	"slices"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+const DefaultWhichKeyDelay = 500 * time.Millisecond
+
+// WhichKey lists the keys that can follow a pending key sequence, in the
+// bottom right corner of the window.
+type WhichKey struct {
+	widget.BaseWidget
+
+	content    *fyne.Container
+	background *canvas.Rectangle
+}
+
+func NewWhichKey() *WhichKey {
+	wk := &WhichKey{
+		content:    container.NewVBox(),
+		background: canvas.NewRectangle(color.Transparent),
+	}
+
+	wk.ExtendBaseWidget(wk)
+	wk.Hide()
+
+	return wk
+}
+
+func (wk *WhichKey) CreateRenderer() fyne.WidgetRenderer {
+	wk.background.FillColor = wk.Theme().Color(ColorNameFloatingBackground, theme.VariantDark)
+
+	return widget.NewSimpleRenderer(container.NewBorder(
+		nil,
+		container.NewHBox(layout.NewSpacer(), container.NewStack(wk.background, wk.content)),
+		nil,
+		nil,
+	))
+}
+
+// SetContinuations shows the keys that can follow pending, with their
+// descriptions.
+func (wk *WhichKey) SetContinuations(pending string, descriptions map[string]string) {
+	keys := []string{}
+	for k := range descriptions {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	objects := []fyne.CanvasObject{
+		widget.NewLabelWithStyle(pending, fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+	}
+	for _, k := range keys {
+		objects = append(objects, widget.NewLabel(fmt.Sprintf("%s: %s", k, descriptions[k])))
+	}
+
+	wk.content.Objects = objects
+	wk.content.Refresh()
+}
+
+func (wk *WhichKey) Refresh() {
+	wk.background.FillColor = wk.Theme().Color(ColorNameFloatingBackground, theme.VariantDark)
+
+	wk.BaseWidget.Refresh()
+}
@@ -14,6 +14,45 @@ type Mover interface {
 	MoveDown()
 	MoveLeft()
 	MoveRight()
+
+	MovePageUp()
+	MovePageDown()
+	MoveHalfPageUp()
+	MoveHalfPageDown()
+	MoveTop()
+	MoveBottom()
+	MoveFirstColumn()
+	MoveLastColumn()
+}
+
+type RowCounter interface {
+	RowCount() int
+	CurrentRow() int
+}
+
+// VisualSelector is implemented by widgets that can select several rows in
+// visual mode, starting from the current one.
+type VisualSelector interface {
+	VisualStart()
+	VisualStop()
+}
+
+// Inserter is implemented by widgets where text is typed in insert mode.
+type Inserter interface {
+	InsertStart()
+	InsertStop()
+}
+
+// TextSelector is implemented by widgets that can return the text of their
+// selection, or of the current row if nothing is selected.
+type TextSelector interface {
+	SelectedText() string
+}
+
+// RowsTexter is implemented by widgets that can return the text of several
+// rows from the current one without selecting them.
+type RowsTexter interface {
+	RowsText(count int) string
 }
 
 type Submitter interface {
@@ -27,6 +66,11 @@ type KeyBinder interface {
 	WidgetName() string
 }
 
+// keyBinderWidgetNames holds the names returned by the WidgetName methods,
+// under which the key bindings of the widgets are kept in
+// settings.key_bindings.
+var keyBinderWidgetNames = []string{"table", "request_response_viewer", "scratch_buffer"}
+
 type Message = any
 
 type MessageHandler interface {